@@ -17,6 +17,7 @@ func init() {
 
 type testPeer struct {
 	id     p2p.ID
+	base   int64
 	height int64
 }
 
@@ -25,7 +26,7 @@ func makePeers(numPeers int, minHeight, maxHeight int64) map[p2p.ID]testPeer {
 	for i := 0; i < numPeers; i++ {
 		peerID := p2p.ID(cmn.RandStr(12))
 		height := minHeight + cmn.RandInt63n(maxHeight-minHeight)
-		peers[peerID] = testPeer{peerID, height}
+		peers[peerID] = testPeer{peerID, 1, height}
 	}
 	return peers
 }
@@ -48,7 +49,7 @@ func TestBasic(t *testing.T) {
 	// Introduce each peer.
 	go func() {
 		for _, peer := range peers {
-			pool.SetPeerHeight(peer.id, peer.height)
+			pool.SetPeerRange(peer.id, peer.base, peer.height)
 		}
 	}()
 
@@ -107,7 +108,7 @@ func TestTimeout(t *testing.T) {
 	// Introduce each peer.
 	go func() {
 		for _, peer := range peers {
-			pool.SetPeerHeight(peer.id, peer.height)
+			pool.SetPeerRange(peer.id, peer.base, peer.height)
 		}
 	}()
 
@@ -145,3 +146,24 @@ func TestTimeout(t *testing.T) {
 		}
 	}
 }
+
+func TestPoolSkipsPeersWithPrunedBlocks(t *testing.T) {
+	pool := NewBlockPool(1, make(chan BlockRequest), make(chan peerError))
+	pool.SetLogger(log.TestingLogger())
+
+	pool.SetPeerRange("pruned", 50, 100)
+	peer := pool.pickIncrAvailablePeer(10)
+	if peer != nil {
+		t.Fatalf("expected no peer to serve height 10, got %v", peer.id)
+	}
+
+	pool.SetPeerRange("archive", 1, 100)
+	peer = pool.pickIncrAvailablePeer(10)
+	if peer == nil || peer.id != "archive" {
+		t.Fatalf("expected the archive peer to serve height 10, got %v", peer)
+	}
+	peer = pool.pickIncrAvailablePeer(60)
+	if peer == nil {
+		t.Fatal("expected a peer to serve height 60")
+	}
+}
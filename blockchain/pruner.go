@@ -0,0 +1,114 @@
+package blockchain
+
+import (
+	"context"
+	"sync/atomic"
+
+	cmn "github.com/tendermint/tendermint/libs/common"
+
+	"github.com/tendermint/tendermint/types"
+)
+
+const (
+	prunerSubscriber = "BlockPruner"
+
+	// buffer for new block headers, so the event bus is never blocked on us
+	prunerBufferSize = 100
+)
+
+// Pruner connects the event bus and the block store together in order to
+// drop blocks that fall out of the retention window as new blocks are
+// committed.
+//
+// Pruning runs in its own routine, which only looks at the latest height, so
+// a slow prune (e.g. of the whole chain when first enabled) neither blocks the
+// event bus nor queues up work for every block committed meanwhile.
+type Pruner struct {
+	cmn.BaseService
+
+	store        *BlockStore
+	eventBus     *types.EventBus
+	retainBlocks int64
+
+	latestHeight int64         // atomic
+	pruneCh      chan struct{} // signals latestHeight changed
+}
+
+// NewPruner returns a new service which keeps the most recent retainBlocks
+// blocks in the store.
+func NewPruner(store *BlockStore, eventBus *types.EventBus, retainBlocks int64) *Pruner {
+	p := &Pruner{
+		store:        store,
+		eventBus:     eventBus,
+		retainBlocks: retainBlocks,
+		pruneCh:      make(chan struct{}, 1),
+	}
+	p.BaseService = *cmn.NewBaseService(nil, "BlockPruner", p)
+	return p
+}
+
+// OnStart implements cmn.Service by subscribing to new block headers and
+// pruning the store after them.
+func (p *Pruner) OnStart() error {
+	blockHeadersCh := make(chan interface{}, prunerBufferSize)
+	if err := p.eventBus.Subscribe(context.Background(), prunerSubscriber, types.EventQueryNewBlockHeader, blockHeadersCh); err != nil {
+		return err
+	}
+
+	go p.receiveRoutine(blockHeadersCh)
+	go p.pruneRoutine()
+	return nil
+}
+
+// OnStop implements cmn.Service by unsubscribing from new block headers.
+func (p *Pruner) OnStop() {
+	if p.eventBus.IsRunning() {
+		_ = p.eventBus.UnsubscribeAll(context.Background(), prunerSubscriber)
+	}
+}
+
+func (p *Pruner) receiveRoutine(blockHeadersCh <-chan interface{}) {
+	for {
+		select {
+		case e, ok := <-blockHeadersCh:
+			if !ok {
+				return
+			}
+			height := e.(types.EventDataNewBlockHeader).Header.Height
+			atomic.StoreInt64(&p.latestHeight, height)
+			select {
+			case p.pruneCh <- struct{}{}:
+			default: // a prune is already pending
+			}
+		case <-p.Quit():
+			return
+		}
+	}
+}
+
+func (p *Pruner) pruneRoutine() {
+	for {
+		select {
+		case <-p.pruneCh:
+			p.prune(atomic.LoadInt64(&p.latestHeight))
+		case <-p.Quit():
+			return
+		}
+	}
+}
+
+func (p *Pruner) prune(height int64) {
+	// never prune past what has actually been saved
+	retainHeight := cmn.MinInt64(height-p.retainBlocks+1, p.store.Height())
+	if retainHeight <= p.store.Base() {
+		return
+	}
+	pruned, err := p.store.PruneBlocks(retainHeight)
+	if err != nil {
+		p.Logger.Error("Failed to prune blocks", "retainHeight", retainHeight, "err", err)
+		return
+	}
+	if pruned > 0 {
+		p.Logger.Info("Pruned blocks", "pruned", pruned, "retainHeight", retainHeight)
+	}
+}
@@ -0,0 +1,41 @@
+package blockchain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestPrunerKeepsRetainBlocks(t *testing.T) {
+	state, bs := makeStateAndBlockStore(log.TestingLogger())
+
+	eventBus := types.NewEventBus()
+	require.NoError(t, eventBus.Start())
+	defer eventBus.Stop()
+
+	pruner := NewPruner(bs, eventBus, 5)
+	pruner.SetLogger(log.TestingLogger())
+	require.NoError(t, pruner.Start())
+	defer pruner.Stop()
+
+	for h := int64(1); h <= 12; h++ {
+		block := makeBlock(h, state)
+		bs.SaveBlock(block, block.MakePartSet(2), &types.Commit{})
+		err := eventBus.PublishEventNewBlockHeader(types.EventDataNewBlockHeader{Header: block.Header})
+		require.NoError(t, err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for bs.Base() != 8 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.EqualValues(t, 8, bs.Base())
+	assert.EqualValues(t, 12, bs.Height())
+	assert.Nil(t, bs.LoadBlock(7))
+	assert.NotNil(t, bs.LoadBlock(8))
+}
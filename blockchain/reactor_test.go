@@ -3,6 +3,7 @@ package blockchain
 import (
 	"net"
 	"testing"
+	"time"
 
 	cmn "github.com/tendermint/tendermint/libs/common"
 	dbm "github.com/tendermint/tendermint/libs/db"
@@ -207,3 +208,4 @@ func (tp *bcrTestPeer) Get(s string) interface{}             { return s }
 func (tp *bcrTestPeer) Set(string, interface{})              {}
 func (tp *bcrTestPeer) RemoteIP() net.IP                     { return []byte{127, 0, 0, 1} }
 func (tp *bcrTestPeer) OriginalAddr() *p2p.NetAddress        { return nil }
+func (tp *bcrTestPeer) FlushStop(time.Duration)              { tp.Stop() }
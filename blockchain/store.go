@@ -7,16 +7,18 @@ import (
 	cmn "github.com/tendermint/tendermint/libs/common"
 	dbm "github.com/tendermint/tendermint/libs/db"
 
+	sm "github.com/tendermint/tendermint/state"
 	"github.com/tendermint/tendermint/types"
 )
 
 /*
 BlockStore is a simple low level store for blocks.
 
-There are three types of information stored:
+There are four types of information stored:
  - BlockMeta:   Meta information about each block
  - Block part:  Parts of each block, aggregated w/ PartSet
  - Commit:      The commit part of each block, for gossiping precommit votes
+ - Block hash:  The height of each block, by its hash
 
 Currently the precommit signatures are duplicated in the Block parts as
 well as the Commit.  In the future this may change, perhaps by moving
@@ -28,7 +30,10 @@ the Commit data outside the Block. (TODO)
 type BlockStore struct {
 	db dbm.DB
 
+	// mtx guards base and height, and is held for reading while loading a
+	// block so PruneBlocks can't delete it halfway through.
 	mtx    sync.RWMutex
+	base   int64
 	height int64
 }
 
@@ -36,12 +41,25 @@ type BlockStore struct {
 // initialized to the last height that was committed to the DB.
 func NewBlockStore(db dbm.DB) *BlockStore {
 	bsjson := LoadBlockStoreStateJSON(db)
+	if bsjson.Base == 0 && bsjson.Height > 0 {
+		// stores from before pruning existed have every block
+		bsjson.Base = 1
+	}
 	return &BlockStore{
+		base:   bsjson.Base,
 		height: bsjson.Height,
 		db:     db,
 	}
 }
 
+// Base returns the first known contiguous block height, or 0 for an empty
+// store. It is above 1 once blocks have been pruned.
+func (bs *BlockStore) Base() int64 {
+	bs.mtx.RLock()
+	defer bs.mtx.RUnlock()
+	return bs.base
+}
+
 // Height returns the last known contiguous block height.
 func (bs *BlockStore) Height() int64 {
 	bs.mtx.RLock()
@@ -52,7 +70,29 @@ func (bs *BlockStore) Height() int64 {
 // LoadBlock returns the block with the given height.
 // If no block is found for that height, it returns nil.
 func (bs *BlockStore) LoadBlock(height int64) *types.Block {
-	var blockMeta = bs.LoadBlockMeta(height)
+	bs.mtx.RLock()
+	defer bs.mtx.RUnlock()
+	return bs.loadBlock(height)
+}
+
+// LoadBlockChecked is like LoadBlock, but it returns state.ErrBlockPruned if
+// the block has been pruned, and state.ErrUnknownBlock if there is no block
+// at that height.
+func (bs *BlockStore) LoadBlockChecked(height int64) (*types.Block, error) {
+	bs.mtx.RLock()
+	defer bs.mtx.RUnlock()
+	if height < bs.base {
+		return nil, sm.ErrBlockPruned{Height: height, Base: bs.base}
+	}
+	block := bs.loadBlock(height)
+	if block == nil {
+		return nil, sm.ErrUnknownBlock{Height: height}
+	}
+	return block, nil
+}
+
+func (bs *BlockStore) loadBlock(height int64) *types.Block {
+	var blockMeta = bs.loadBlockMeta(height)
 	if blockMeta == nil {
 		return nil
 	}
@@ -60,7 +100,7 @@ func (bs *BlockStore) LoadBlock(height int64) *types.Block {
 	var block = new(types.Block)
 	buf := []byte{}
 	for i := 0; i < blockMeta.BlockID.PartsHeader.Total; i++ {
-		part := bs.LoadBlockPart(height, i)
+		part := bs.loadBlockPart(height, i)
 		buf = append(buf, part.Bytes...)
 	}
 	err := cdc.UnmarshalBinary(buf, block)
@@ -72,10 +112,112 @@ func (bs *BlockStore) LoadBlock(height int64) *types.Block {
 	return block
 }
 
+// LoadBlockByHash returns the block with the given hash.
+// If no block is found for that hash, it returns nil.
+// Blocks saved before the hash index existed are only found once
+// IndexBlockHashes has run.
+func (bs *BlockStore) LoadBlockByHash(hash []byte) *types.Block {
+	height, ok := bs.loadHeightByHash(hash)
+	if !ok {
+		return nil
+	}
+	return bs.LoadBlock(height)
+}
+
+func (bs *BlockStore) loadHeightByHash(hash []byte) (int64, bool) {
+	bz := bs.db.Get(calcBlockHashKey(hash))
+	if len(bz) == 0 {
+		return 0, false
+	}
+	var height int64
+	err := cdc.UnmarshalBinaryBare(bz, &height)
+	if err != nil {
+		panic(cmn.ErrorWrap(err, "Error reading block height"))
+	}
+	return height, true
+}
+
+// IndexBlockHashes adds the blocks saved before the hash index existed to
+// it, up to the first block that is already indexed, and returns the number
+// of blocks indexed. Blocks are indexed in batches of pruneBatchSize, each
+// of which records where to carry on from, so a run stopped by closing quit
+// resumes there the next time.
+func (bs *BlockStore) IndexBlockHashes(quit <-chan struct{}) int64 {
+	if bs.Height() == 0 {
+		return 0
+	}
+	indexed := int64(0)
+	for from := bs.loadHashIndexCursor(); ; {
+		select {
+		case <-quit:
+			return indexed
+		default:
+		}
+		n, next, done := bs.indexBlockHashes(from, from+pruneBatchSize)
+		indexed += n
+		if done {
+			return indexed
+		}
+		from = next
+	}
+}
+
+// indexBlockHashes indexes the blocks in [from, to), and saves the height to
+// carry on from. It returns true once it reaches an indexed block or the top
+// of the store, since every block saved after the index existed is in it.
+func (bs *BlockStore) indexBlockHashes(from, to int64) (indexed, next int64, done bool) {
+	// hold off pruning, so no hash of a deleted block is put back
+	bs.mtx.RLock()
+	defer bs.mtx.RUnlock()
+
+	batch := bs.db.NewBatch()
+	next = cmn.MaxInt64(from, bs.base)
+	for ; next < to; next++ {
+		if next > bs.height {
+			done = true
+			break
+		}
+		meta := bs.loadBlockMeta(next)
+		if meta == nil {
+			continue
+		}
+		if _, ok := bs.loadHeightByHash(meta.BlockID.Hash); ok {
+			done = true
+			break
+		}
+		batch.Set(calcBlockHashKey(meta.BlockID.Hash), cdc.MustMarshalBinaryBare(next))
+		indexed++
+	}
+	batch.Set(blockHashIndexKey, cdc.MustMarshalBinaryBare(next))
+	batch.WriteSync()
+	return indexed, next, done
+}
+
+// loadHashIndexCursor returns the height IndexBlockHashes carries on from,
+// or 0 if it never ran.
+func (bs *BlockStore) loadHashIndexCursor() int64 {
+	bz := bs.db.Get(blockHashIndexKey)
+	if len(bz) == 0 {
+		return 0
+	}
+	var height int64
+	err := cdc.UnmarshalBinaryBare(bz, &height)
+	if err != nil {
+		panic(cmn.ErrorWrap(err, "Error reading block hash index height"))
+	}
+	return height
+}
+
 // LoadBlockPart returns the Part at the given index
 // from the block at the given height.
 // If no part is found for the given height and index, it returns nil.
 func (bs *BlockStore) LoadBlockPart(height int64, index int) *types.Part {
+	bs.mtx.RLock()
+	defer bs.mtx.RUnlock()
+	return bs.loadBlockPart(height, index)
+}
+
+func (bs *BlockStore) loadBlockPart(height int64, index int) *types.Part {
 	var part = new(types.Part)
 	bz := bs.db.Get(calcBlockPartKey(height, index))
 	if len(bz) == 0 {
@@ -91,6 +233,12 @@ func (bs *BlockStore) LoadBlockPart(height int64, index int) *types.Part {
 // LoadBlockMeta returns the BlockMeta for the given height.
 // If no block is found for the given height, it returns nil.
 func (bs *BlockStore) LoadBlockMeta(height int64) *types.BlockMeta {
+	bs.mtx.RLock()
+	defer bs.mtx.RUnlock()
+	return bs.loadBlockMeta(height)
+}
+
+func (bs *BlockStore) loadBlockMeta(height int64) *types.BlockMeta {
 	var blockMeta = new(types.BlockMeta)
 	bz := bs.db.Get(calcBlockMetaKey(height))
 	if len(bz) == 0 {
@@ -158,6 +306,7 @@ func (bs *BlockStore) SaveBlock(block *types.Block, blockParts *types.PartSet, s
 	blockMeta := types.NewBlockMeta(block, blockParts)
 	metaBytes := cdc.MustMarshalBinaryBare(blockMeta)
 	bs.db.Set(calcBlockMetaKey(height), metaBytes)
+	bs.db.Set(calcBlockHashKey(blockMeta.BlockID.Hash), cdc.MustMarshalBinaryBare(height))
 
 	// Save block parts
 	for i := 0; i < blockParts.Total(); i++ {
@@ -175,17 +324,73 @@ func (bs *BlockStore) SaveBlock(block *types.Block, blockParts *types.PartSet, s
 	bs.db.Set(calcSeenCommitKey(height), seenCommitBytes)
 
 	// Save new BlockStoreStateJSON descriptor
-	BlockStoreStateJSON{Height: height}.Save(bs.db)
-
-	// Done!
 	bs.mtx.Lock()
 	bs.height = height
+	if bs.base == 0 {
+		bs.base = height
+	}
+	BlockStoreStateJSON{Base: bs.base, Height: height}.Save(bs.db)
 	bs.mtx.Unlock()
 
 	// Flush
 	bs.db.SetSync(nil, nil)
 }
 
+// PruneBlocks removes all blocks below retainHeight, along with their parts
+// and commits, and returns the number of blocks pruned. Blocks are deleted in
+// batches, each of which also moves the base up atomically, so a crash never
+// leaves the base pointing at a partially deleted block.
+func (bs *BlockStore) PruneBlocks(retainHeight int64) (uint64, error) {
+	if retainHeight <= 0 {
+		return 0, fmt.Errorf("height must be greater than 0")
+	}
+	if height := bs.Height(); retainHeight > height {
+		return 0, fmt.Errorf("cannot prune beyond the latest height %v", height)
+	}
+	base := bs.Base()
+	if retainHeight <= base {
+		return 0, nil
+	}
+
+	pruned := uint64(0)
+	for base < retainHeight {
+		end := cmn.MinInt64(base+pruneBatchSize, retainHeight)
+		bs.pruneRange(base, end)
+		pruned += uint64(end - base)
+		base = end
+	}
+	return pruned, nil
+}
+
+// pruneRange deletes the blocks in [from, to) and moves the base to `to`.
+func (bs *BlockStore) pruneRange(from, to int64) {
+	bs.mtx.Lock()
+	defer bs.mtx.Unlock()
+
+	batch := bs.db.NewBatch()
+	for h := from; h < to; h++ {
+		meta := bs.loadBlockMeta(h)
+		if meta == nil {
+			continue
+		}
+		batch.Delete(calcBlockMetaKey(h))
+		batch.Delete(calcBlockHashKey(meta.BlockID.Hash))
+		for i := 0; i < meta.BlockID.PartsHeader.Total; i++ {
+			batch.Delete(calcBlockPartKey(h, i))
+		}
+		batch.Delete(calcBlockCommitKey(h))
+		batch.Delete(calcSeenCommitKey(h))
+	}
+	bs.base = to
+	bsj := BlockStoreStateJSON{Base: bs.base, Height: bs.height}
+	bytes, err := cdc.MarshalJSON(bsj)
+	if err != nil {
+		cmn.PanicSanity(cmn.Fmt("Could not marshal state bytes: %v", err))
+	}
+	batch.Set(blockStoreKey, bytes)
+	batch.WriteSync()
+}
+
 func (bs *BlockStore) saveBlockPart(height int64, index int, part *types.Part) {
 	if height != bs.Height()+1 {
 		cmn.PanicSanity(cmn.Fmt("BlockStore can only save contiguous blocks. Wanted %v, got %v", bs.Height()+1, height))
@@ -212,11 +417,23 @@ func calcSeenCommitKey(height int64) []byte {
 	return []byte(fmt.Sprintf("SC:%v", height))
 }
 
+func calcBlockHashKey(hash []byte) []byte {
+	return []byte(fmt.Sprintf("BH:%X", hash))
+}
+
 //-----------------------------------------------------------------------------
 
 var blockStoreKey = []byte("blockStore")
 
+// blockHashIndexKey holds the next height to add to the block hash index,
+// see IndexBlockHashes.
+var blockHashIndexKey = []byte("blockHashIndex")
+
+// Number of heights deleted per DB batch by PruneBlocks.
+const pruneBatchSize = 1000
+
 type BlockStoreStateJSON struct {
+	Base   int64 `json:"base"`
 	Height int64 `json:"height"`
 }
 
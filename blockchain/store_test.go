@@ -13,6 +13,7 @@ import (
 	"github.com/tendermint/tendermint/libs/db"
 	"github.com/tendermint/tendermint/libs/log"
 
+	sm "github.com/tendermint/tendermint/state"
 	"github.com/tendermint/tendermint/types"
 )
 
@@ -353,6 +354,158 @@ func TestBlockFetchAtHeight(t *testing.T) {
 	require.Nil(t, blockAtHeightPlus2, "expecting an unsuccessful load of Height()+2")
 }
 
+func TestBlockStorePruneBlocks(t *testing.T) {
+	state, bs := makeStateAndBlockStore(log.NewTMLogger(new(bytes.Buffer)))
+	assert.EqualValues(t, 0, bs.Base())
+
+	const numBlocks = 30
+	for h := int64(1); h <= numBlocks; h++ {
+		block := makeBlock(h, state)
+		partSet := block.MakePartSet(2)
+		seenCommit := &types.Commit{Precommits: []*types.Vote{{Height: h, Timestamp: time.Now().UTC()}}}
+		bs.SaveBlock(block, partSet, seenCommit)
+	}
+	assert.EqualValues(t, 1, bs.Base())
+	assert.EqualValues(t, numBlocks, bs.Height())
+
+	_, err := bs.PruneBlocks(0)
+	assert.Error(t, err)
+	_, err = bs.PruneBlocks(numBlocks + 1)
+	assert.Error(t, err)
+
+	pruned, err := bs.PruneBlocks(10)
+	require.NoError(t, err)
+	assert.EqualValues(t, 9, pruned)
+	assert.EqualValues(t, 10, bs.Base())
+	assert.EqualValues(t, numBlocks, bs.Height())
+
+	for h := int64(1); h < 10; h++ {
+		assert.Nil(t, bs.LoadBlock(h), "height %d", h)
+		assert.Nil(t, bs.LoadBlockMeta(h), "height %d", h)
+		assert.Nil(t, bs.LoadBlockPart(h, 0), "height %d", h)
+		assert.Nil(t, bs.LoadBlockCommit(h), "height %d", h)
+		assert.Nil(t, bs.LoadSeenCommit(h), "height %d", h)
+	}
+	assert.NotNil(t, bs.LoadBlock(10))
+	assert.NotNil(t, bs.LoadSeenCommit(10))
+
+	_, err = bs.LoadBlockChecked(9)
+	assert.Equal(t, sm.ErrBlockPruned{Height: 9, Base: 10}, err)
+	_, err = bs.LoadBlockChecked(numBlocks + 1)
+	assert.Equal(t, sm.ErrUnknownBlock{Height: numBlocks + 1}, err)
+	block, err := bs.LoadBlockChecked(10)
+	require.NoError(t, err)
+	assert.EqualValues(t, 10, block.Height)
+
+	// pruning below the base is a no-op
+	pruned, err = bs.PruneBlocks(5)
+	require.NoError(t, err)
+	assert.EqualValues(t, 0, pruned)
+
+	// the base survives a restart, and saving continues as usual
+	bs = NewBlockStore(bs.db)
+	assert.EqualValues(t, 10, bs.Base())
+	block = makeBlock(numBlocks+1, state)
+	bs.SaveBlock(block, block.MakePartSet(2), &types.Commit{})
+	assert.EqualValues(t, 10, bs.Base())
+
+	pruned, err = bs.PruneBlocks(numBlocks + 1)
+	require.NoError(t, err)
+	assert.EqualValues(t, numBlocks-9, pruned)
+	assert.EqualValues(t, numBlocks+1, bs.Base())
+	assert.NotNil(t, bs.LoadBlock(numBlocks+1))
+}
+
+func TestBlockStoreBaseOfOldStore(t *testing.T) {
+	db := db.NewMemDB()
+	db.Set(blockStoreKey, []byte(`{"height": "10"}`))
+	bs := NewBlockStore(db)
+	assert.EqualValues(t, 1, bs.Base())
+
+	// saving new blocks keeps the old ones
+	state, _ := makeStateAndBlockStore(log.NewTMLogger(new(bytes.Buffer)))
+	block := makeBlock(11, state)
+	bs.SaveBlock(block, block.MakePartSet(2), &types.Commit{})
+	assert.EqualValues(t, 1, bs.Base())
+	assert.EqualValues(t, 11, bs.Height())
+	assert.EqualValues(t, 1, NewBlockStore(db).Base())
+}
+
+func TestLoadBlockByHash(t *testing.T) {
+	state, bs := makeStateAndBlockStore(log.NewTMLogger(new(bytes.Buffer)))
+
+	blocks := make([]*types.Block, 5)
+	for i := range blocks {
+		h := int64(i + 1)
+		blocks[i] = makeBlock(h, state)
+		bs.SaveBlock(blocks[i], blocks[i].MakePartSet(2), &types.Commit{})
+	}
+	for _, block := range blocks {
+		got := bs.LoadBlockByHash(block.Hash())
+		require.NotNil(t, got, "height %d", block.Height)
+		assert.Equal(t, block.Height, got.Height)
+	}
+	assert.Nil(t, bs.LoadBlockByHash([]byte("bogus")))
+
+	// stores without the index only find old blocks once they're indexed
+	for _, block := range blocks[:3] {
+		bs.db.Delete(calcBlockHashKey(block.Hash()))
+	}
+	bs = NewBlockStore(bs.db)
+	assert.Nil(t, bs.LoadBlockByHash(blocks[0].Hash()))
+	assert.EqualValues(t, 3, bs.IndexBlockHashes(nil))
+	got := bs.LoadBlockByHash(blocks[0].Hash())
+	require.NotNil(t, got)
+	assert.EqualValues(t, 1, got.Height)
+	for _, block := range blocks {
+		_, ok := bs.loadHeightByHash(block.Hash())
+		assert.True(t, ok, "height %d", block.Height)
+	}
+	assert.EqualValues(t, 0, bs.IndexBlockHashes(nil))
+
+	// pruned blocks are gone from the index
+	_, err := bs.PruneBlocks(3)
+	require.NoError(t, err)
+	assert.Nil(t, bs.LoadBlockByHash(blocks[0].Hash()))
+	_, ok := bs.loadHeightByHash(blocks[0].Hash())
+	assert.False(t, ok)
+	assert.NotNil(t, bs.LoadBlockByHash(blocks[2].Hash()))
+}
+
+func TestIndexBlockHashesBatches(t *testing.T) {
+	state, bs := makeStateAndBlockStore(log.NewTMLogger(new(bytes.Buffer)))
+
+	numBlocks := int64(pruneBatchSize + 10)
+	hashes := make([][]byte, numBlocks)
+	for h := int64(1); h <= numBlocks; h++ {
+		block := makeBlock(h, state)
+		bs.SaveBlock(block, block.MakePartSet(2), &types.Commit{})
+		hashes[h-1] = block.Hash()
+		bs.db.Delete(calcBlockHashKey(block.Hash()))
+	}
+
+	// a closed quit channel stops before the first batch
+	quit := make(chan struct{})
+	close(quit)
+	assert.EqualValues(t, 0, bs.IndexBlockHashes(quit))
+
+	// stop after the first batch, as if the node was stopped
+	indexed, next, done := bs.indexBlockHashes(bs.loadHashIndexCursor(), 1+pruneBatchSize)
+	assert.EqualValues(t, pruneBatchSize, indexed)
+	assert.EqualValues(t, 1+pruneBatchSize, next)
+	assert.False(t, done)
+
+	// the next run carries on after it, rather than stopping at block 1
+	bs = NewBlockStore(bs.db)
+	assert.EqualValues(t, numBlocks-pruneBatchSize, bs.IndexBlockHashes(nil))
+	assert.EqualValues(t, 0, bs.IndexBlockHashes(nil))
+	for i, hash := range hashes {
+		height, ok := bs.loadHeightByHash(hash)
+		require.True(t, ok, "height %d", i+1)
+		assert.EqualValues(t, i+1, height)
+	}
+}
+
 func doFn(fn func() (interface{}, error)) (res interface{}, err error, panicErr error) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -1,7 +1,10 @@
 package commands
 
 import (
+	"fmt"
 	"os"
+	"reflect"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -23,21 +26,77 @@ func init() {
 
 func registerFlagsRootCmd(cmd *cobra.Command) {
 	cmd.PersistentFlags().String("log_level", config.LogLevel, "Log level")
+	cmd.PersistentFlags().String("log_format", config.LogFormat, "Log format (plain|json)")
 }
 
 // ParseConfig retrieves the default environment configuration,
 // sets up the Tendermint root and ensures that the root exists
 func ParseConfig() (*cfg.Config, error) {
 	conf := cfg.DefaultConfig()
+	if err := bindEnvKeys(conf); err != nil {
+		return nil, err
+	}
 	err := viper.Unmarshal(conf)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("Error parsing config (check TM_* environment variables): %v", err)
 	}
 	conf.SetRoot(conf.RootDir)
 	cfg.EnsureRoot(conf.RootDir)
+	if err := conf.ValidateBasic(); err != nil {
+		return nil, fmt.Errorf("Error in config file: %v", err)
+	}
 	return conf, err
 }
 
+// bindEnvKeys binds every field of the config to its TM_-prefixed
+// environment variable (eg. rpc.laddr -> TM_RPC_LADDR). Viper's AutomaticEnv
+// only consults the environment for keys it already knows about, so without
+// this a nested field missing from config.toml could not be overridden.
+// Precedence stays file < env < flags.
+func bindEnvKeys(conf *cfg.Config) error {
+	return walkConfig("", reflect.ValueOf(conf).Elem(), func(key string, _ reflect.Value) error {
+		return viper.BindEnv(key)
+	})
+}
+
+// walkConfig calls fn with the viper key and value of every leaf field of
+// the struct v, following its mapstructure tags.
+func walkConfig(prefix string, v reflect.Value, fn func(key string, v reflect.Value) error) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue // unexported
+		}
+		tag := f.Tag.Get("mapstructure")
+		if tag == "" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		fv := v.Field(i)
+		if fv.Kind() == reflect.Ptr && fv.Type().Elem().Kind() == reflect.Struct {
+			if fv.IsNil() {
+				fv = reflect.New(fv.Type().Elem())
+			}
+			fv = fv.Elem()
+		}
+		if fv.Kind() == reflect.Struct {
+			sub := prefix
+			if name != "" {
+				sub = prefix + name + "."
+			}
+			if err := walkConfig(sub, fv, fn); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := fn(prefix+name, fv); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // RootCmd is the root command for Tendermint core.
 var RootCmd = &cobra.Command{
 	Use:   "tendermint",
@@ -50,6 +109,9 @@ var RootCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
+		if config.LogFormat == cfg.LogFormatJSON {
+			logger = log.NewTMJSONLogger(log.NewSyncWriter(os.Stdout))
+		}
 		logger, err = tmflags.ParseLogLevel(config.LogLevel, logger, cfg.DefaultLogLevel())
 		if err != nil {
 			return err
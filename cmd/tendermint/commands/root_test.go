@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -14,8 +15,12 @@ import (
 	"github.com/stretchr/testify/require"
 
 	cfg "github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/crypto/ed25519"
 	"github.com/tendermint/tendermint/libs/cli"
 	cmn "github.com/tendermint/tendermint/libs/common"
+	"github.com/tendermint/tendermint/libs/log"
+	nm "github.com/tendermint/tendermint/node"
+	"github.com/tendermint/tendermint/types"
 )
 
 var (
@@ -119,6 +124,27 @@ func TestRootFlagsEnv(t *testing.T) {
 	}
 }
 
+func TestRootLogFormat(t *testing.T) {
+	cases := []struct {
+		args      []string
+		env       map[string]string
+		logFormat string
+	}{
+		{nil, nil, cfg.LogFormatPlain},
+		{[]string{"--log_format", "json"}, nil, cfg.LogFormatJSON},
+		{nil, map[string]string{"TM_LOG_FORMAT": "json"}, cfg.LogFormatJSON},
+	}
+
+	for i, tc := range cases {
+		idxString := strconv.Itoa(i)
+
+		err := testSetup(defaultRoot, tc.args, tc.env)
+		require.Nil(t, err, idxString)
+
+		assert.Equal(t, tc.logFormat, config.LogFormat, idxString)
+	}
+}
+
 func TestRootConfig(t *testing.T) {
 
 	// write non-default config
@@ -164,6 +190,149 @@ func TestRootConfig(t *testing.T) {
 	}
 }
 
+func TestRootConfigNestedEnv(t *testing.T) {
+	// the config file only sets rpc.laddr; p2p.laddr and
+	// mempool.size are absent and must still be overridable from the env
+	fileLaddr := "tcp://127.0.0.1:36657"
+	envLaddr := "tcp://127.0.0.1:46657"
+	flagLaddr := "tcp://127.0.0.1:56657"
+
+	cases := []struct {
+		args []string
+		env  map[string]string
+
+		rpcLaddr string
+		p2pLaddr string
+		size     int
+	}{
+		{nil, nil, fileLaddr, cfg.DefaultP2PConfig().ListenAddress, cfg.DefaultMempoolConfig().Size},
+		{nil, map[string]string{"TM_RPC_LADDR": envLaddr}, envLaddr, cfg.DefaultP2PConfig().ListenAddress, cfg.DefaultMempoolConfig().Size},
+		{[]string{"--rpc.laddr", flagLaddr}, map[string]string{"TM_RPC_LADDR": envLaddr}, flagLaddr, cfg.DefaultP2PConfig().ListenAddress, cfg.DefaultMempoolConfig().Size},
+		{nil, map[string]string{"TM_P2P_LADDR": envLaddr, "TM_MEMPOOL_SIZE": "42"}, fileLaddr, envLaddr, 42},
+	}
+
+	for i, tc := range cases {
+		idxString := strconv.Itoa(i)
+		clearConfig(defaultRoot)
+
+		configFilePath := filepath.Join(defaultRoot, "config")
+		err := cmn.EnsureDir(configFilePath, 0700)
+		require.Nil(t, err)
+		data := fmt.Sprintf("[rpc]\nladdr = \"%s\"\n", fileLaddr)
+		err = ioutil.WriteFile(filepath.Join(configFilePath, "config.toml"), []byte(data), 0666)
+		require.Nil(t, err)
+
+		rootCmd := testRootCmd()
+		rootCmd.PersistentFlags().String("rpc.laddr", config.RPC.ListenAddress, "RPC listen address")
+		cmd := cli.PrepareBaseCmd(rootCmd, "TM", defaultRoot)
+
+		tc.args = append([]string{rootCmd.Use}, tc.args...)
+		err = cli.RunWithArgs(cmd, tc.args, tc.env)
+		require.Nil(t, err, idxString)
+
+		assert.Equal(t, tc.rpcLaddr, config.RPC.ListenAddress, idxString)
+		assert.Equal(t, tc.p2pLaddr, config.P2P.ListenAddress, idxString)
+		assert.Equal(t, tc.size, config.Mempool.Size, idxString)
+	}
+}
+
+func TestRootConfigEnvBadType(t *testing.T) {
+	clearConfig(defaultRoot)
+
+	rootCmd := testRootCmd()
+	cmd := cli.PrepareBaseCmd(rootCmd, "TM", defaultRoot)
+	cmd.Exit = func(int) {}
+
+	args := []string{rootCmd.Use}
+	err := cli.RunWithArgs(cmd, args, map[string]string{"TM_MEMPOOL_SIZE": "lots"})
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "size")
+}
+
+func TestRootNodeDBFlags(t *testing.T) {
+	home, err := ioutil.TempDir("", "tendermint-home")
+	require.Nil(t, err)
+	defer os.RemoveAll(home) // nolint: errcheck
+
+	cases := []struct {
+		args []string
+		env  map[string]string
+
+		backend string
+		dbDir   string
+	}{
+		{nil, nil, cfg.DefaultBaseConfig().DBBackend, filepath.Join(home, "data")},
+		{[]string{"--db_backend", "memdb", "--db_dir", "otherdata"}, nil, "memdb", filepath.Join(home, "otherdata")},
+		{[]string{"--db_dir", "/tmp/absdata"}, nil, cfg.DefaultBaseConfig().DBBackend, "/tmp/absdata"},
+		{[]string{"--db_backend", "memdb"}, map[string]string{"TM_DB_BACKEND": "fsdb", "TM_DB_DIR": "envdata"}, "memdb", filepath.Join(home, "envdata")},
+	}
+
+	for i, tc := range cases {
+		idxString := strconv.Itoa(i)
+		clearConfig(defaultRoot)
+
+		rootCmd := testRootCmd()
+		AddNodeFlags(rootCmd)
+		cmd := cli.PrepareBaseCmd(rootCmd, "TM", defaultRoot)
+
+		args := append([]string{rootCmd.Use, "--home", home}, tc.args...)
+		err := cli.RunWithArgs(cmd, args, tc.env)
+		require.Nil(t, err, idxString)
+
+		assert.Equal(t, home, config.RootDir, idxString)
+		assert.Equal(t, tc.backend, config.DBBackend, idxString)
+		assert.Equal(t, tc.dbDir, config.DBDir(), idxString)
+	}
+}
+
+func TestRunNodeNetwork(t *testing.T) {
+	home, err := ioutil.TempDir("", "tendermint-home")
+	require.Nil(t, err)
+	defer os.RemoveAll(home) // nolint: errcheck
+
+	pubKey := ed25519.GenPrivKey().PubKey()
+	for _, chainID := range []string{"main-chain", "test-chain"} {
+		genDoc := &types.GenesisDoc{
+			ChainID:    chainID,
+			Validators: []types.GenesisValidator{{PubKey: pubKey, Power: 10, Name: "val"}},
+		}
+		require.Nil(t, cmn.EnsureDir(filepath.Join(home, "config"), 0700))
+		require.Nil(t, genDoc.SaveAs(filepath.Join(home, "config", chainID+".json")))
+	}
+
+	// the node itself is never started
+	errNoNode := errors.New("no node")
+	nodeProvider := func(*cfg.Config, log.Logger) (*nm.Node, error) {
+		return nil, errNoNode
+	}
+
+	cases := []struct {
+		args []string
+		err  string
+	}{
+		{[]string{"--genesis_file", "config/main-chain.json"}, errNoNode.Error()},
+		{[]string{"--genesis_file", "config/main-chain.json", "--network", "main-chain"}, errNoNode.Error()},
+		{[]string{"--genesis_file", "config/test-chain.json", "--network", "test-chain"}, errNoNode.Error()},
+		{[]string{"--genesis_file", "config/test-chain.json", "--network", "main-chain"}, `is for network "test-chain", not "main-chain"`},
+		{[]string{"--genesis_file", "config/missing.json", "--network", "main-chain"}, "Failed to load genesis file"},
+	}
+
+	for i, tc := range cases {
+		idxString := strconv.Itoa(i)
+		clearConfig(defaultRoot)
+
+		rootCmd := testRootCmd()
+		rootCmd.AddCommand(NewRunNodeCmd(nodeProvider))
+		cmd := cli.PrepareBaseCmd(rootCmd, "TM", defaultRoot)
+		cmd.Exit = func(int) {}
+
+		args := append([]string{rootCmd.Use, "node", "--home", home}, tc.args...)
+		err := cli.RunWithArgs(cmd, args, nil)
+		require.NotNil(t, err, idxString)
+		assert.Contains(t, err.Error(), tc.err, idxString)
+	}
+}
+
 // WriteConfigVals writes a toml file with the given values.
 // It returns an error if writing was impossible.
 func WriteConfigVals(dir string, vals map[string]string) error {
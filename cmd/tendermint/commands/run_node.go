@@ -6,6 +6,7 @@ import (
 	"github.com/spf13/cobra"
 
 	nm "github.com/tendermint/tendermint/node"
+	"github.com/tendermint/tendermint/types"
 )
 
 // AddNodeFlags exposes some common configuration options on the command-line
@@ -19,6 +20,11 @@ func AddNodeFlags(cmd *cobra.Command) {
 
 	// node flags
 	cmd.Flags().Bool("fast_sync", config.FastSync, "Fast blockchain syncing")
+	cmd.Flags().String("genesis_file", config.Genesis, "Genesis file, relative to home unless absolute")
+
+	// db flags
+	cmd.Flags().String("db_backend", config.DBBackend, "Database backend (leveldb | memdb | cleveldb | fsdb)")
+	cmd.Flags().String("db_dir", config.DBPath, "Database directory, relative to home unless absolute")
 
 	// abci flags
 	cmd.Flags().String("proxy_app", config.ProxyApp, "Proxy app address, or 'nilapp' or 'kvstore' for local testing.")
@@ -49,6 +55,16 @@ func NewRunNodeCmd(nodeProvider nm.NodeProvider) *cobra.Command {
 		Use:   "node",
 		Short: "Run the tendermint node",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			network, err := cmd.Flags().GetString("network")
+			if err != nil {
+				return err
+			}
+			if network != "" {
+				if err := checkNetwork(config.GenesisFile(), network); err != nil {
+					return err
+				}
+			}
+
 			// Create & start node
 			n, err := nodeProvider(config, logger)
 			if err != nil {
@@ -68,5 +84,19 @@ func NewRunNodeCmd(nodeProvider nm.NodeProvider) *cobra.Command {
 	}
 
 	AddNodeFlags(cmd)
+	cmd.Flags().String("network", "", "Chain ID of the network to join. The node refuses to start if the genesis file is for another chain")
 	return cmd
 }
+
+// checkNetwork returns an error if the genesis file is not for the chain
+// with the given ID.
+func checkNetwork(genesisFile, network string) error {
+	genDoc, err := types.GenesisDocFromFile(genesisFile)
+	if err != nil {
+		return fmt.Errorf("Failed to load genesis file: %v", err)
+	}
+	if genDoc.ChainID != network {
+		return fmt.Errorf("Genesis file %v is for network %q, not %q", genesisFile, genDoc.ChainID, network)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	cfg "github.com/tendermint/tendermint/config"
+)
+
+// ShowConfigCmd dumps the effective configuration to the standard output.
+// The config holds no secrets, only paths to the key files, so nothing is
+// left out.
+var ShowConfigCmd = &cobra.Command{
+	Use:   "show_config",
+	Short: "Show the effective configuration and where each value came from",
+	RunE:  showConfig,
+}
+
+func showConfig(cmd *cobra.Command, args []string) error {
+	return writeEffectiveConfig(os.Stdout, cmd, config)
+}
+
+// effectiveConfig is what show_config prints. Config mirrors config.toml,
+// Sources lists the keys that were not left at their default, with where
+// their value came from: file, env or flag.
+type effectiveConfig struct {
+	Config  map[string]interface{} `json:"config"`
+	Sources map[string]string      `json:"sources"`
+}
+
+// writeEffectiveConfig writes the merged config as indented JSON.
+func writeEffectiveConfig(w io.Writer, cmd *cobra.Command, conf *cfg.Config) error {
+	file := viper.New()
+	if path := viper.ConfigFileUsed(); path != "" {
+		file.SetConfigFile(path)
+		if err := file.ReadInConfig(); err != nil {
+			return err
+		}
+	}
+
+	out := effectiveConfig{
+		Config:  make(map[string]interface{}),
+		Sources: make(map[string]string),
+	}
+	err := walkConfig("", reflect.ValueOf(conf).Elem(), func(key string, v reflect.Value) error {
+		if strings.HasSuffix(key, ".home") {
+			return nil // sub configs inherit the root dir
+		}
+		setConfigValue(out.Config, key, formatConfigValue(v))
+		if source := configSource(cmd, file, key); source != "default" {
+			out.Sources[key] = source
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	bz, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", bz)
+	return err
+}
+
+// setConfigValue sets the dotted key (eg. rpc.laddr) in the nested map m.
+func setConfigValue(m map[string]interface{}, key string, value interface{}) {
+	parts := strings.Split(key, ".")
+	for _, part := range parts[:len(parts)-1] {
+		sub, ok := m[part].(map[string]interface{})
+		if !ok {
+			sub = make(map[string]interface{})
+			m[part] = sub
+		}
+		m = sub
+	}
+	m[parts[len(parts)-1]] = value
+}
+
+// configSource reports the highest precedence source that set key.
+func configSource(cmd *cobra.Command, file *viper.Viper, key string) string {
+	if f := cmd.Flags().Lookup(key); f != nil && f.Changed {
+		return "flag"
+	}
+	// persistent flags of the parent commands, eg. --log_level
+	if f := cmd.InheritedFlags().Lookup(key); f != nil && f.Changed {
+		return "flag"
+	}
+	env := "TM_" + strings.ToUpper(strings.Replace(key, ".", "_", -1))
+	if os.Getenv(env) != "" {
+		return "env"
+	}
+	if file.IsSet(key) {
+		return "file"
+	}
+	return "default"
+}
+
+func formatConfigValue(v reflect.Value) interface{} {
+	if d, ok := v.Interface().(time.Duration); ok {
+		return d.String() // as written in config.toml
+	}
+	return v.Interface()
+}
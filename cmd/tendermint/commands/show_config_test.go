@@ -0,0 +1,80 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/libs/cli"
+	cmn "github.com/tendermint/tendermint/libs/common"
+)
+
+func TestShowConfigSources(t *testing.T) {
+	clearConfig(defaultRoot)
+
+	configFilePath := filepath.Join(defaultRoot, "config")
+	require.Nil(t, cmn.EnsureDir(configFilePath, 0700))
+	data := "moniker = \"from-file\"\n[rpc]\nladdr = \"tcp://127.0.0.1:36657\"\n"
+	require.Nil(t, ioutil.WriteFile(filepath.Join(configFilePath, "config.toml"), []byte(data), 0666))
+
+	// RunWithArgs restores the env when it returns, but the source of each
+	// value is resolved afterwards
+	require.Nil(t, os.Setenv("TM_MEMPOOL_SIZE", "42"))
+	defer os.Unsetenv("TM_MEMPOOL_SIZE") // nolint: errcheck
+
+	// run it as a subcommand, so --log_level is an inherited flag
+	buf := new(bytes.Buffer)
+	rootCmd := testRootCmd()
+	rootCmd.AddCommand(&cobra.Command{
+		Use: "show_config",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return writeEffectiveConfig(buf, cmd, config)
+		},
+	})
+	cmd := cli.PrepareBaseCmd(rootCmd, "TM", defaultRoot)
+	args := []string{rootCmd.Use, "show_config", "--log_level", "debug"}
+	require.Nil(t, cli.RunWithArgs(cmd, args, nil))
+
+	var out struct {
+		Config struct {
+			LogLevel    string `json:"log_level"`
+			Moniker     string `json:"moniker"`
+			PrivValFile string `json:"priv_validator_file"`
+			NodeKeyFile string `json:"node_key_file"`
+			Mempool     struct {
+				Size int `json:"size"`
+			} `json:"mempool"`
+			RPC map[string]interface{} `json:"rpc"`
+			P2P map[string]interface{} `json:"p2p"`
+		} `json:"config"`
+		Sources map[string]string `json:"sources"`
+	}
+	require.Nil(t, json.Unmarshal(buf.Bytes(), &out), buf.String())
+	assert.Contains(t, buf.String(), "\n  \"config\": {\n", "should be indented")
+
+	assert.Equal(t, "debug", out.Config.LogLevel)
+	assert.Equal(t, "from-file", out.Config.Moniker)
+	assert.Equal(t, 42, out.Config.Mempool.Size)
+	assert.Equal(t, "tcp://127.0.0.1:36657", out.Config.RPC["laddr"])
+	assert.Equal(t, "tcp://0.0.0.0:26656", out.Config.P2P["laddr"])
+	assert.Equal(t, "20s", out.Config.P2P["handshake_timeout"])
+	assert.NotContains(t, out.Config.RPC, "home")
+
+	// key file paths are shown as configured
+	assert.Equal(t, config.PrivValidator, out.Config.PrivValFile)
+	assert.Equal(t, config.NodeKey, out.Config.NodeKeyFile)
+
+	assert.Equal(t, map[string]string{
+		"log_level":    "flag",
+		"mempool.size": "env",
+		"moniker":      "file",
+		"rpc.laddr":    "file",
+	}, out.Sources)
+}
@@ -27,6 +27,8 @@ var (
 	hostnamePrefix          string
 	startingIPAddress       string
 	p2pPort                 int
+
+	validatorsSeed string
 )
 
 const (
@@ -51,6 +53,8 @@ func init() {
 		"Starting IP address (192.168.0.1 results in persistent peers list ID0@192.168.0.1:26656, ID1@192.168.0.2:26656, ...)")
 	TestnetFilesCmd.Flags().IntVar(&p2pPort, "p2p-port", 26656,
 		"P2P Port")
+	TestnetFilesCmd.Flags().StringVar(&validatorsSeed, "seed", "",
+		"Derive validator keys from this secret instead of generating random ones (seed results in keys from seed:0, seed:1, ...)")
 }
 
 // TestnetFilesCmd allows initialisation of files for a Tendermint testnet.
@@ -67,6 +71,9 @@ Optionally, it will fill in persistent_peers list in config file using either ho
 Example:
 
 	tendermint testnet --v 4 --o ./output --populate-persistent-peers --starting-ip-address 192.168.10.2
+
+Passing --seed makes the validator keys reproducible across runs, which is
+handy for test networks that get rebuilt often. Never use it for real keys.
 	`,
 	RunE: testnetFiles,
 }
@@ -86,9 +93,13 @@ func testnetFiles(cmd *cobra.Command, args []string) error {
 			return err
 		}
 
+		pvFile := filepath.Join(nodeDir, config.BaseConfig.PrivValidator)
+		if validatorsSeed != "" {
+			privval.GenFilePVFromSecret(pvFile, validatorSecret(validatorsSeed, i)).Save()
+		}
+
 		initFilesWithConfig(config)
 
-		pvFile := filepath.Join(nodeDir, config.BaseConfig.PrivValidator)
 		pv := privval.LoadFilePV(pvFile)
 		genVals[i] = types.GenesisValidator{
 			PubKey: pv.GetPubKey(),
@@ -181,3 +192,9 @@ func populatePersistentPeersInConfigAndWriteIt(config *cfg.Config) error {
 
 	return nil
 }
+
+// validatorSecret returns the secret the i-th validator key is derived from
+// when --seed is given, ie. "<seed>:<i>".
+func validatorSecret(seed string, i int) []byte {
+	return []byte(fmt.Sprintf("%s:%d", seed, i))
+}
@@ -2,9 +2,14 @@ package config
 
 import (
 	"fmt"
+	"net"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
+
+	cmn "github.com/tendermint/tendermint/libs/common"
 )
 
 const (
@@ -12,6 +17,11 @@ const (
 	FuzzModeDrop = iota
 	// FuzzModeDelay is a mode in which we randomly sleep
 	FuzzModeDelay
+
+	// LogFormatPlain is a format for colored text
+	LogFormatPlain = "plain"
+	// LogFormatJSON is a format for json output
+	LogFormatJSON = "json"
 )
 
 // NOTE: Most of the structs & relevant comments + the
@@ -89,6 +99,126 @@ func (cfg *Config) SetRoot(root string) *Config {
 	return cfg
 }
 
+// ValidateBasic performs basic validation (checking listen addresses, port
+// collisions, known backends, etc.) and returns an error listing every
+// problem found, so an operator can fix the config file in one go.
+func (cfg *Config) ValidateBasic() error {
+	var errs []string
+	addErr := func(field string, err error) {
+		errs = append(errs, fmt.Sprintf("%s: %v", field, err))
+	}
+
+	switch cfg.DBBackend {
+	case "leveldb", "goleveldb", "cleveldb", "memdb", "fsdb":
+	default:
+		addErr("db_backend", fmt.Errorf("unknown backend %q", cfg.DBBackend))
+	}
+	if fi, err := os.Stat(cfg.DBDir()); err == nil && !fi.IsDir() {
+		addErr("db_dir", fmt.Errorf("%s exists and is not a directory", cfg.DBDir()))
+	}
+	if cfg.RetainBlocks < 0 || cfg.RetainBlocks == 1 {
+		addErr("retain_blocks", fmt.Errorf("must be 0 (keep all) or at least 2, got %d", cfg.RetainBlocks))
+	}
+	switch cfg.LogFormat {
+	case LogFormatPlain, LogFormatJSON:
+	default:
+		addErr("log_format", fmt.Errorf("unknown format %q (must be plain or json)", cfg.LogFormat))
+	}
+	switch cfg.ABCI {
+	case "socket", "grpc":
+	default:
+		addErr("abci", fmt.Errorf("unknown transport %q (must be socket or grpc)", cfg.ABCI))
+	}
+
+	// every TCP port we listen on must be usable and distinct
+	ports := make(map[int]string)
+	checkAddr := func(field, addr string) {
+		port, err := listenPort(addr)
+		if err != nil {
+			addErr(field, err)
+			return
+		}
+		if port == 0 {
+			return
+		}
+		if other, ok := ports[port]; ok {
+			addErr(field, fmt.Errorf("port %d is already used by %s", port, other))
+			return
+		}
+		ports[port] = field
+	}
+	checkAddr("p2p.laddr", cfg.P2P.ListenAddress)
+	// an empty rpc.laddr disables the RPC server, or it lists several addresses
+	for _, addr := range cmn.SplitAndTrim(cfg.RPC.ListenAddress, ",", " ") {
+		checkAddr("rpc.laddr", addr)
+	}
+	if cfg.RPC.GRPCListenAddress != "" {
+		checkAddr("rpc.grpc_laddr", cfg.RPC.GRPCListenAddress)
+	}
+	if cfg.PrivValidatorListenAddr != "" {
+		checkAddr("priv_validator_laddr", cfg.PrivValidatorListenAddr)
+	}
+	if cfg.ProfListenAddress != "" {
+		checkAddr("prof_laddr", cfg.ProfListenAddress)
+	}
+	if cfg.Instrumentation.Prometheus {
+		checkAddr("instrumentation.prometheus_listen_addr", cfg.Instrumentation.PrometheusListenAddr)
+	}
+
+	if cfg.P2P.MaxNumPeers < 0 {
+		addErr("p2p.max_num_peers", fmt.Errorf("can't be negative"))
+	}
+	if cfg.RPC.TimeoutBroadcastTxCommit <= 0 {
+		addErr("rpc.timeout_broadcast_tx_commit", fmt.Errorf("must be positive"))
+	}
+	if cfg.Mempool.Size < 0 {
+		addErr("mempool.size", fmt.Errorf("can't be negative"))
+	}
+	if cfg.Mempool.MaxTxsBytes < 0 {
+		addErr("mempool.max_txs_bytes", fmt.Errorf("can't be negative"))
+	}
+	if cfg.Mempool.TTLDuration < 0 {
+		addErr("mempool.ttl_duration", fmt.Errorf("can't be negative"))
+	}
+	if cfg.Mempool.TTLNumBlocks < 0 {
+		addErr("mempool.ttl_num_blocks", fmt.Errorf("can't be negative"))
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration:\n  %s", strings.Join(errs, "\n  "))
+	}
+	return nil
+}
+
+// listenPort returns the TCP port of a listen address such as
+// "tcp://0.0.0.0:26656" or ":26660". A unix socket address has no port and
+// returns 0.
+func listenPort(addr string) (int, error) {
+	protocol, address := "tcp", addr
+	if parts := strings.SplitN(addr, "://", 2); len(parts) == 2 {
+		protocol, address = parts[0], parts[1]
+	}
+	switch protocol {
+	case "unix":
+		if address == "" {
+			return 0, fmt.Errorf("empty unix socket path in %q", addr)
+		}
+		return 0, nil
+	case "tcp":
+	default:
+		return 0, fmt.Errorf("unknown protocol %q in %q", protocol, addr)
+	}
+	_, portStr, err := net.SplitHostPort(address)
+	if err != nil {
+		return 0, fmt.Errorf("invalid address %q: %v", addr, err)
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil || port < 0 || port > 65535 {
+		return 0, fmt.Errorf("invalid port %q in %q", portStr, addr)
+	}
+	return port, nil
+}
+
 //-----------------------------------------------------------------------------
 // BaseConfig
 
@@ -128,6 +258,9 @@ type BaseConfig struct {
 	// Output level for logging
 	LogLevel string `mapstructure:"log_level"`
 
+	// Output format: 'plain' (colored text) or 'json'
+	LogFormat string `mapstructure:"log_format"`
+
 	// TCP or UNIX socket address for the profiling server to listen on
 	ProfListenAddress string `mapstructure:"prof_laddr"`
 
@@ -145,6 +278,11 @@ type BaseConfig struct {
 
 	// Database directory
 	DBPath string `mapstructure:"db_dir"`
+
+	// Number of recent blocks to keep in the block store; older blocks are
+	// pruned as new ones are committed. 0 keeps every block. The app must
+	// persist its state, as pruned blocks can't be replayed to it.
+	RetainBlocks int64 `mapstructure:"retain_blocks"`
 }
 
 // DefaultBaseConfig returns a default base configuration for a Tendermint node
@@ -157,11 +295,13 @@ func DefaultBaseConfig() BaseConfig {
 		ProxyApp:          "tcp://127.0.0.1:26658",
 		ABCI:              "socket",
 		LogLevel:          DefaultPackageLogLevels(),
+		LogFormat:         LogFormatPlain,
 		ProfListenAddress: "",
 		FastSync:          true,
 		FilterPeers:       false,
 		DBBackend:         "leveldb",
 		DBPath:            "data",
+		RetainBlocks:      0,
 	}
 }
 
@@ -240,6 +380,9 @@ type RPCConfig struct {
 	// you increase your OS limits.
 	// 0 - unlimited.
 	MaxOpenConnections int `mapstructure:"max_open_connections"`
+
+	// How long to wait for a tx to be committed during /broadcast_tx_commit.
+	TimeoutBroadcastTxCommit time.Duration `mapstructure:"timeout_broadcast_tx_commit"`
 }
 
 // DefaultRPCConfig returns a default configuration for the RPC server
@@ -254,6 +397,8 @@ func DefaultRPCConfig() *RPCConfig {
 		// should be < {ulimit -Sn} - {MaxNumPeers} - {N of wal, db and other open files}
 		// 1024 - 50 - 50 = 924 = ~900
 		MaxOpenConnections: 900,
+
+		TimeoutBroadcastTxCommit: 2 * time.Minute,
 	}
 }
 
@@ -326,10 +471,18 @@ type P2PConfig struct {
 	// Toggle to disable guard against peers connecting from the same ip.
 	AllowDuplicateIP bool `mapstructure:"allow_duplicate_ip"`
 
+	// Accept peers on a different network (chain ID). Only useful for nodes
+	// bridging or monitoring several networks.
+	AllowNetworkMismatch bool `mapstructure:"allow_network_mismatch"`
+
 	// Peer connection configuration.
 	HandshakeTimeout time.Duration `mapstructure:"handshake_timeout"`
 	DialTimeout      time.Duration `mapstructure:"dial_timeout"`
 
+	// Time allowed on shutdown to send what is queued for each peer. 0 drops
+	// the connections right away.
+	StopTimeout time.Duration `mapstructure:"stop_timeout"`
+
 	// Testing params.
 	// Force dial to fail
 	TestDialFail bool `mapstructure:"test_dial_fail"`
@@ -354,8 +507,10 @@ func DefaultP2PConfig() *P2PConfig {
 		PexReactor:              true,
 		SeedMode:                false,
 		AllowDuplicateIP:        true, // so non-breaking yet
+		AllowNetworkMismatch:    false,
 		HandshakeTimeout:        20 * time.Second,
 		DialTimeout:             3 * time.Second,
+		StopTimeout:             1 * time.Second,
 		TestDialFail:            false,
 		TestFuzz:                false,
 		TestFuzzConfig:          DefaultFuzzConnConfig(),
@@ -407,7 +562,13 @@ type MempoolConfig struct {
 	Broadcast    bool   `mapstructure:"broadcast"`
 	WalPath      string `mapstructure:"wal_dir"`
 	Size         int    `mapstructure:"size"`
+	MaxTxsBytes  int64  `mapstructure:"max_txs_bytes"`
 	CacheSize    int    `mapstructure:"cache_size"`
+
+	// Txs that stay in the mempool longer than TTLDuration, or for more than
+	// TTLNumBlocks blocks, are expired. 0 disables either limit.
+	TTLDuration  time.Duration `mapstructure:"ttl_duration"`
+	TTLNumBlocks int64         `mapstructure:"ttl_num_blocks"`
 }
 
 // DefaultMempoolConfig returns a default configuration for the Tendermint mempool
@@ -418,7 +579,10 @@ func DefaultMempoolConfig() *MempoolConfig {
 		Broadcast:    true,
 		WalPath:      filepath.Join(defaultDataDir, "mempool.wal"),
 		Size:         100000,
+		MaxTxsBytes:  1024 * 1024 * 1024, // 1GB
 		CacheSize:    100000,
+		TTLDuration:  0,
+		TTLNumBlocks: 0,
 	}
 }
 
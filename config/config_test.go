@@ -1,9 +1,13 @@
 package config
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -26,3 +30,93 @@ func TestDefaultConfig(t *testing.T) {
 	assert.Equal("/foo/wal/mem", cfg.Mempool.WalDir())
 
 }
+
+func TestConfigValidateBasic(t *testing.T) {
+	assert.NoError(t, DefaultConfig().ValidateBasic())
+	assert.NoError(t, TestConfig().ValidateBasic())
+
+	testCases := []struct {
+		name     string
+		malleate func(*Config)
+		errField string
+	}{
+		{"p2p laddr without port", func(c *Config) { c.P2P.ListenAddress = "0.0.0.0" }, "p2p.laddr"},
+		{"rpc laddr bad port", func(c *Config) { c.RPC.ListenAddress = "tcp://0.0.0.0:http" }, "rpc.laddr"},
+		{"rpc laddr port out of range", func(c *Config) { c.RPC.ListenAddress = "tcp://0.0.0.0:70000" }, "rpc.laddr"},
+		{"rpc laddr unknown protocol", func(c *Config) { c.RPC.ListenAddress = "udp://0.0.0.0:26657" }, "rpc.laddr"},
+		{"empty unix socket", func(c *Config) { c.RPC.ListenAddress = "unix://" }, "rpc.laddr"},
+		{"rpc and p2p share a port", func(c *Config) { c.RPC.ListenAddress = "tcp://127.0.0.1:26656" }, "port 26656 is already used by p2p.laddr"},
+		{"bad address in rpc laddr list", func(c *Config) { c.RPC.ListenAddress = "tcp://0.0.0.0:26657, tcp://0.0.0.0:http" }, "rpc.laddr"},
+		{"rpc laddr list shares a port", func(c *Config) { c.RPC.ListenAddress = "tcp://0.0.0.0:26657,tcp://127.0.0.1:26657" }, "port 26657 is already used by rpc.laddr"},
+		{"grpc and rpc share a port", func(c *Config) { c.RPC.GRPCListenAddress = "tcp://0.0.0.0:26657" }, "rpc.grpc_laddr"},
+		{"prometheus collides when enabled", func(c *Config) {
+			c.Instrumentation.Prometheus = true
+			c.Instrumentation.PrometheusListenAddr = ":26657"
+		}, "instrumentation.prometheus_listen_addr"},
+		{"unknown db backend", func(c *Config) { c.DBBackend = "rocksdb" }, "db_backend"},
+		{"retain one block", func(c *Config) { c.RetainBlocks = 1 }, "retain_blocks"},
+		{"negative retain blocks", func(c *Config) { c.RetainBlocks = -1 }, "retain_blocks"},
+		{"unknown log format", func(c *Config) { c.LogFormat = "xml" }, "log_format"},
+		{"unknown abci transport", func(c *Config) { c.ABCI = "http" }, "abci"},
+		{"negative max peers", func(c *Config) { c.P2P.MaxNumPeers = -1 }, "p2p.max_num_peers"},
+		{"negative broadcast commit timeout", func(c *Config) { c.RPC.TimeoutBroadcastTxCommit = -1 }, "rpc.timeout_broadcast_tx_commit"},
+		{"zero broadcast commit timeout", func(c *Config) { c.RPC.TimeoutBroadcastTxCommit = 0 }, "rpc.timeout_broadcast_tx_commit"},
+		{"negative mempool size", func(c *Config) { c.Mempool.Size = -1 }, "mempool.size"},
+		{"negative mempool bytes", func(c *Config) { c.Mempool.MaxTxsBytes = -1 }, "mempool.max_txs_bytes"},
+		{"negative mempool ttl", func(c *Config) { c.Mempool.TTLNumBlocks = -1 }, "mempool.ttl_num_blocks"},
+	}
+	for _, tc := range testCases {
+		cfg := DefaultConfig()
+		tc.malleate(cfg)
+		err := cfg.ValidateBasic()
+		if assert.Error(t, err, tc.name) {
+			assert.Contains(t, err.Error(), tc.errField, tc.name)
+		}
+	}
+
+	// unix sockets and port 0 never collide
+	cfg := DefaultConfig()
+	cfg.RPC.ListenAddress = "unix:///tmp/rpc.sock"
+	cfg.P2P.ListenAddress = "tcp://0.0.0.0:0"
+	cfg.RPC.GRPCListenAddress = "tcp://0.0.0.0:0"
+	assert.NoError(t, cfg.ValidateBasic())
+
+	// an empty rpc.laddr disables the RPC server
+	cfg = DefaultConfig()
+	cfg.RPC.ListenAddress = ""
+	assert.NoError(t, cfg.ValidateBasic())
+
+	// and it can list several addresses
+	cfg = DefaultConfig()
+	cfg.RPC.ListenAddress = "tcp://0.0.0.0:26657, unix:///tmp/rpc.sock"
+	assert.NoError(t, cfg.ValidateBasic())
+}
+
+func TestConfigValidateBasicListsAllErrors(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.P2P.ListenAddress = "0.0.0.0"
+	cfg.DBBackend = "rocksdb"
+	cfg.ABCI = "http"
+
+	err := cfg.ValidateBasic()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "p2p.laddr")
+	assert.Contains(t, err.Error(), "db_backend")
+	assert.Contains(t, err.Error(), "abci")
+}
+
+func TestConfigValidateBasicDBDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config_test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	cfg := DefaultConfig()
+	cfg.SetRoot(dir)
+	assert.NoError(t, cfg.ValidateBasic(), "a missing db dir is fine, the node creates it")
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "data"), []byte("x"), 0600))
+	err = cfg.ValidateBasic()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "db_dir")
+	}
+}
@@ -83,9 +83,18 @@ db_backend = "{{ .BaseConfig.DBBackend }}"
 # Database directory
 db_path = "{{ js .BaseConfig.DBPath }}"
 
+# Number of recent blocks to keep in the block store (0 keeps every block).
+# The latest two blocks are always needed, so any other value must be >= 2.
+# The app must persist its state: the node refuses to start if the app needs
+# blocks which have been pruned to catch up.
+retain_blocks = {{ .BaseConfig.RetainBlocks }}
+
 # Output level for logging, including package level options
 log_level = "{{ .BaseConfig.LogLevel }}"
 
+# Output format: 'plain' (colored text) or 'json'
+log_format = "{{ .BaseConfig.LogFormat }}"
+
 ##### additional base config options #####
 
 # Path to the JSON file containing the initial validator set and other meta data
@@ -136,6 +145,9 @@ unsafe = {{ .RPC.Unsafe }}
 # 0 - unlimited.
 max_open_connections = {{ .RPC.MaxOpenConnections }}
 
+# How long to wait for a tx to be committed during /broadcast_tx_commit.
+timeout_broadcast_tx_commit = "{{ .RPC.TimeoutBroadcastTxCommit }}"
+
 ##### peer to peer configuration options #####
 [p2p]
 
@@ -190,6 +202,10 @@ seed_mode = {{ .P2P.SeedMode }}
 # Comma separated list of peer IDs to keep private (will not be gossiped to other peers)
 private_peer_ids = "{{ .P2P.PrivatePeerIDs }}"
 
+# Accept peers on a different network (chain ID).
+# Only useful for nodes bridging or monitoring several networks.
+allow_network_mismatch = {{ .P2P.AllowNetworkMismatch }}
+
 ##### mempool configuration options #####
 [mempool]
 
@@ -201,9 +217,18 @@ wal_dir = "{{ js .Mempool.WalPath }}"
 # size of the mempool
 size = {{ .Mempool.Size }}
 
+# limit on the total size of all txs in the mempool (0 means no limit)
+max_txs_bytes = {{ .Mempool.MaxTxsBytes }}
+
 # size of the cache (used to filter transactions we saw earlier)
 cache_size = {{ .Mempool.CacheSize }}
 
+# txs older than ttl_duration, or that have been in the mempool for more than
+# ttl_num_blocks blocks, are removed when the next block is committed.
+# 0 disables either limit.
+ttl_duration = "{{ .Mempool.TTLDuration }}"
+ttl_num_blocks = {{ .Mempool.TTLNumBlocks }}
+
 ##### consensus configuration options #####
 [consensus]
 
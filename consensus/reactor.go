@@ -251,6 +251,10 @@ func (conR *ConsensusReactor) Receive(chID byte, src p2p.Peer, msgBytes []byte)
 		}
 		switch msg := msg.(type) {
 		case *ProposalMessage:
+			if err := msg.Proposal.ValidateBasic(); err != nil {
+				conR.Switch.StopPeerForError(src, err)
+				return
+			}
 			ps.SetHasProposal(msg.Proposal)
 			conR.conS.peerMsgQueue <- msgInfo{msg, src.ID()}
 		case *ProposalPOLMessage:
@@ -272,6 +276,10 @@ func (conR *ConsensusReactor) Receive(chID byte, src p2p.Peer, msgBytes []byte)
 		}
 		switch msg := msg.(type) {
 		case *VoteMessage:
+			if err := msg.Vote.ValidateBasic(); err != nil {
+				conR.Switch.StopPeerForError(src, err)
+				return
+			}
 			cs := conR.conS
 			cs.mtx.Lock()
 			height, valSize, lastCommitSize := cs.Height, cs.Validators.Size(), cs.LastCommit.Size()
@@ -478,9 +486,19 @@ OUTER_LOOP:
 		if (0 < prs.Height) && (prs.Height < rs.Height) {
 			heightLogger := logger.With("height", prs.Height)
 
+			// we've pruned the blocks the peer needs, it has to catch up elsewhere
+			if base := conR.conS.blockStore.Base(); prs.Height < base {
+				heightLogger.Debug("Peer is below our base, can't help it catch up", "base", base)
+				time.Sleep(conR.conS.config.PeerGossipSleep())
+				continue OUTER_LOOP
+			}
+
 			// if we never received the commit message from the peer, the block parts wont be initialized
 			if prs.ProposalBlockParts == nil {
 				blockMeta := conR.conS.blockStore.LoadBlockMeta(prs.Height)
+				if blockMeta == nil && prs.Height < conR.conS.blockStore.Base() {
+					continue OUTER_LOOP // pruned since we checked
+				}
 				if blockMeta == nil {
 					cmn.PanicCrisis(cmn.Fmt("Failed to load block %d when blockStore is at %d",
 						prs.Height, conR.conS.blockStore.Height()))
@@ -624,12 +642,12 @@ OUTER_LOOP:
 		}
 
 		// Catchup logic
-		// If peer is lagging by more than 1, send Commit.
-		if prs.Height != 0 && rs.Height >= prs.Height+2 {
+		// If peer is lagging by more than 1, send Commit, unless it's pruned.
+		if prs.Height != 0 && rs.Height >= prs.Height+2 && prs.Height >= conR.conS.blockStore.Base() {
 			// Load the block commit for prs.Height,
 			// which contains precommit signatures for prs.Height.
 			commit := conR.conS.blockStore.LoadBlockCommit(prs.Height)
-			if ps.PickSendVote(commit) {
+			if commit != nil && ps.PickSendVote(commit) {
 				logger.Debug("Picked Catchup commit to send", "height", prs.Height)
 				continue OUTER_LOOP
 			}
@@ -775,15 +793,18 @@ OUTER_LOOP:
 		// Maybe send Height/CatchupCommitRound/CatchupCommit.
 		{
 			prs := ps.GetRoundState()
-			if prs.CatchupCommitRound != -1 && 0 < prs.Height && prs.Height <= conR.conS.blockStore.Height() {
-				commit := conR.conS.LoadCommit(prs.Height)
-				peer.TrySend(StateChannel, cdc.MustMarshalBinaryBare(&VoteSetMaj23Message{
-					Height:  prs.Height,
-					Round:   commit.Round(),
-					Type:    types.VoteTypePrecommit,
-					BlockID: commit.BlockID,
-				}))
-				time.Sleep(conR.conS.config.PeerQueryMaj23Sleep())
+			// (nothing to send if the commit has been pruned)
+			if prs.CatchupCommitRound != -1 && conR.conS.blockStore.Base() <= prs.Height &&
+				0 < prs.Height && prs.Height <= conR.conS.blockStore.Height() {
+				if commit := conR.conS.LoadCommit(prs.Height); commit != nil {
+					peer.TrySend(StateChannel, cdc.MustMarshalBinaryBare(&VoteSetMaj23Message{
+						Height:  prs.Height,
+						Round:   commit.Round(),
+						Type:    types.VoteTypePrecommit,
+						BlockID: commit.BlockID,
+					}))
+					time.Sleep(conR.conS.config.PeerQueryMaj23Sleep())
+				}
 			}
 		}
 
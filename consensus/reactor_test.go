@@ -22,6 +22,7 @@ import (
 	sm "github.com/tendermint/tendermint/state"
 
 	cfg "github.com/tendermint/tendermint/config"
+	cstypes "github.com/tendermint/tendermint/consensus/types"
 	"github.com/tendermint/tendermint/p2p"
 	p2pdummy "github.com/tendermint/tendermint/p2p/dummy"
 	"github.com/tendermint/tendermint/types"
@@ -528,6 +529,43 @@ func TestReactorWithTimeoutCommit(t *testing.T) {
 	}, css)
 }
 
+// Ensure a peer below our pruned base doesn't make us load pruned blocks
+func TestReactorPeerBelowBase(t *testing.T) {
+	css := randConsensusNet(1, "consensus_reactor_peer_below_base_test", NewTimeoutTicker, newCounter)
+	reactors, eventChans, eventBuses := startConsensusNet(t, css, 1)
+	defer stopConsensusNet(log.TestingLogger(), reactors, eventBuses)
+
+	for i := 0; i < 4; i++ {
+		select {
+		case <-eventChans[0]:
+		case <-time.After(10 * time.Second):
+			t.Fatal("Timed out waiting for a new block")
+		}
+	}
+	err := eventBuses[0].Unsubscribe(context.Background(), testSubscriber, types.EventQueryNewBlock)
+	require.NoError(t, err)
+	_, err = css[0].blockStore.(*bc.BlockStore).PruneBlocks(3)
+	require.NoError(t, err)
+
+	// a peer at height 1, which would also like our commit for it
+	peer := p2pdummy.NewPeer()
+	require.NoError(t, peer.Start())
+	defer peer.Stop()
+	reactors[0].AddPeer(peer)
+	bz, err := cdc.MarshalBinaryBare(&NewRoundStepMessage{Height: 1, Round: 0, Step: cstypes.RoundStepPropose})
+	require.NoError(t, err)
+	reactors[0].Receive(StateChannel, peer, bz)
+	ps := peer.Get(types.PeerStateKey).(*PeerState)
+	ps.mtx.Lock()
+	ps.PRS.CatchupCommitRound = 0
+	ps.mtx.Unlock()
+
+	// give every gossip routine a few rounds with the peer
+	time.Sleep(3 * css[0].config.PeerQueryMaj23Sleep())
+	assert.EqualValues(t, 1, ps.GetRoundState().Height)
+	assert.True(t, reactors[0].IsRunning())
+}
+
 func waitForAndValidateBlock(t *testing.T, n int, activeVals map[string]struct{}, eventChans []chan interface{}, css []*ConsensusState, txs ...[]byte) {
 	timeoutWaitGroup(t, n, func(j int) {
 		css[j].Logger.Debug("waitForAndValidateBlock")
@@ -657,3 +695,56 @@ func capture() {
 	count := runtime.Stack(trace, true)
 	fmt.Printf("Stack of %d bytes: %s\n", count, trace)
 }
+
+// Test decoding corrupted or random input returns an error instead of panicking
+// or allocating whatever lengths the input claims.
+func TestReactorDecodeMsgCorruptedInput(t *testing.T) {
+	parts := types.NewPartSetFromData(cmn.RandBytes(100), 10)
+	blockID := types.BlockID{Hash: cmn.RandBytes(20), PartsHeader: parts.Header()}
+	bits := cmn.NewBitArray(10)
+	bits.SetIndex(3, true)
+	msgs := []ConsensusMessage{
+		&NewRoundStepMessage{Height: 2, Round: 1, Step: 1, SecondsSinceStartTime: 5, LastCommitRound: 0},
+		&CommitStepMessage{Height: 2, BlockPartsHeader: parts.Header(), BlockParts: bits},
+		&ProposalMessage{Proposal: types.NewProposal(2, 1, parts.Header(), -1, types.BlockID{})},
+		&ProposalPOLMessage{Height: 2, ProposalPOLRound: 1, ProposalPOL: bits},
+		&BlockPartMessage{Height: 2, Round: 1, Part: parts.GetPart(0)},
+		&VoteMessage{Vote: &types.Vote{ValidatorAddress: cmn.RandBytes(20), Height: 2, Round: 1,
+			Timestamp: time.Now().UTC(), Type: types.VoteTypePrevote, BlockID: blockID, Signature: cmn.RandBytes(64)}},
+		&VoteSetBitsMessage{Height: 2, Round: 1, Type: types.VoteTypePrecommit, BlockID: blockID, Votes: bits},
+	}
+
+	decode := func(bz []byte) {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Fatalf("decodeMsg(%X) panicked: %v", bz, r)
+			}
+		}()
+		decodeMsg(bz) // nolint: errcheck
+	}
+
+	for _, msg := range msgs {
+		bz := cdc.MustMarshalBinaryBare(msg)
+		decoded, err := decodeMsg(bz)
+		require.NoError(t, err)
+		require.Equal(t, cdc.MustMarshalBinaryBare(decoded), bz)
+
+		for i := 0; i < 1000; i++ {
+			corrupted := make([]byte, len(bz))
+			copy(corrupted, bz)
+			for j := 0; j <= cmn.RandIntn(3); j++ {
+				corrupted[cmn.RandIntn(len(corrupted))] = byte(cmn.RandIntn(256))
+			}
+			decode(corrupted)
+			decode(corrupted[:cmn.RandIntn(len(corrupted))])
+		}
+	}
+
+	for i := 0; i < 1000; i++ {
+		decode(cmn.RandBytes(cmn.RandIntn(200)))
+	}
+
+	// too big
+	_, err := decodeMsg(make([]byte, maxMsgSize+1))
+	assert.Error(t, err)
+}
@@ -313,6 +313,13 @@ func (h *Handshaker) ReplayBlocks(state sm.State, appHash []byte, appBlockHeight
 		cmn.PanicSanity(cmn.Fmt("StoreBlockHeight (%d) > StateBlockHeight + 1 (%d)", storeBlockHeight, stateBlockHeight+1))
 	}
 
+	// the blocks the app is missing must still be in the store, which is not
+	// the case for an app that lost its state (e.g. non-persistent) since
+	// blocks were pruned
+	if base := h.store.Base(); appBlockHeight < storeBlockHeight && appBlockHeight+1 < base {
+		return appHash, sm.ErrBlockPruned{Height: appBlockHeight + 1, Base: base}
+	}
+
 	var err error
 	// Now either store is equal to state, or one ahead.
 	// For each, consider all cases of where the app could be, given app <= store
@@ -304,6 +304,43 @@ func TestHandshakeReplayNone(t *testing.T) {
 	}
 }
 
+// Refuse to sync an app which needs blocks that have been pruned
+func TestHandshakeReplayPrunedBlocks(t *testing.T) {
+	config := ResetConfig("proxy_test_")
+
+	walBody, err := WALWithNBlocks(NUM_BLOCKS)
+	require.NoError(t, err)
+	walFile := tempWALWithData(walBody)
+	config.Consensus.SetWalFile(walFile)
+
+	privVal := privval.LoadFilePV(config.PrivValidatorFile())
+
+	wal, err := NewWAL(walFile)
+	require.NoError(t, err)
+	wal.SetLogger(log.TestingLogger())
+	require.NoError(t, wal.Start())
+	defer wal.Stop()
+
+	chain, commits, err := makeBlockchainFromWAL(wal)
+	require.NoError(t, err)
+
+	stateDB, state, store := stateAndStore(config, privVal.GetPubKey())
+	store.chain = chain
+	store.commits = commits
+	store.base = 3
+	state = buildTMStateFromChain(config, stateDB, state, chain, 0)
+
+	// a fresh app needs every block from 1
+	clientCreator := proxy.NewLocalClientCreator(kvstore.NewKVStoreApplication())
+	genDoc, _ := sm.MakeGenesisDocFromFile(config.GenesisFile())
+	handshaker := NewHandshaker(stateDB, state, store, genDoc)
+	proxyApp := proxy.NewAppConns(clientCreator, handshaker)
+	err = proxyApp.Start()
+	defer proxyApp.Stop()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), sm.ErrBlockPruned{Height: 1, Base: 3}.Error())
+}
+
 func tempWALWithData(data []byte) string {
 	walFile, err := ioutil.TempFile("", "wal")
 	if err != nil {
@@ -610,15 +647,23 @@ type mockBlockStore struct {
 	params  types.ConsensusParams
 	chain   []*types.Block
 	commits []*types.Commit
+	base    int64
 }
 
 // TODO: NewBlockStore(db.NewMemDB) ...
 func NewMockBlockStore(config *cfg.Config, params types.ConsensusParams) *mockBlockStore {
-	return &mockBlockStore{config, params, nil, nil}
+	return &mockBlockStore{config, params, nil, nil, 1}
 }
 
+func (bs *mockBlockStore) Base() int64                         { return bs.base }
 func (bs *mockBlockStore) Height() int64                       { return int64(len(bs.chain)) }
 func (bs *mockBlockStore) LoadBlock(height int64) *types.Block { return bs.chain[height-1] }
+func (bs *mockBlockStore) LoadBlockChecked(height int64) (*types.Block, error) {
+	if height < bs.base {
+		return nil, sm.ErrBlockPruned{Height: height, Base: bs.base}
+	}
+	return bs.chain[height-1], nil
+}
 func (bs *mockBlockStore) LoadBlockMeta(height int64) *types.BlockMeta {
 	block := bs.chain[height-1]
 	return &types.BlockMeta{
@@ -626,6 +671,14 @@ func (bs *mockBlockStore) LoadBlockMeta(height int64) *types.BlockMeta {
 		Header:  block.Header,
 	}
 }
+func (bs *mockBlockStore) LoadBlockByHash(hash []byte) *types.Block {
+	for _, block := range bs.chain {
+		if bytes.Equal(block.Hash(), hash) {
+			return block
+		}
+	}
+	return nil
+}
 func (bs *mockBlockStore) LoadBlockPart(height int64, index int) *types.Part { return nil }
 func (bs *mockBlockStore) SaveBlock(block *types.Block, blockParts *types.PartSet, seenCommit *types.Commit) {
 }
@@ -17,6 +17,7 @@ import (
 	"github.com/tendermint/tendermint/libs/log"
 
 	cfg "github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/p2p"
 	"github.com/tendermint/tendermint/proxy"
 	"github.com/tendermint/tendermint/types"
 )
@@ -57,6 +58,19 @@ var (
 	ErrMempoolIsFull = errors.New("Mempool is full")
 )
 
+// TxInfo are parameters that get passed when attempting to add a tx to the
+// mempool.
+type TxInfo struct {
+	// PeerID is the peer the tx was received from, if any. The tx is never
+	// gossiped back to it.
+	PeerID p2p.ID
+}
+
+// ExpiredTxCallback is called for every tx removed from the mempool because
+// it outlived the configured TTL. It's called with the mempool locked, so it
+// must not call back into the mempool.
+type ExpiredTxCallback func(tx types.Tx)
+
 // TxID is the hex encoded hash of the bytes as a types.Tx.
 func TxID(tx []byte) string {
 	return fmt.Sprintf("%X", types.Tx(tx).Hash())
@@ -72,6 +86,8 @@ type Mempool struct {
 	proxyMtx             sync.Mutex
 	proxyAppConn         proxy.AppConnMempool
 	txs                  *clist.CList    // concurrent linked-list of good txs
+	txsBytes             int64           // total size of mem.txs, in bytes
+	txsMap               sync.Map        // tx key -> *clist.CElement, for every tx in mem.txs
 	counter              int64           // simple incrementing counter
 	height               int64           // the last block Update()'d to
 	rechecking           int32           // for re-checking filtered txs on Update()
@@ -80,6 +96,12 @@ type Mempool struct {
 	notifiedTxsAvailable bool
 	txsAvailable         chan struct{} // fires once for each height, when the mempool is not empty
 
+	// Peers that sent txs which are still being checked by the app,
+	// by tx key. Guards txsMap updates too, so no sender is lost while the
+	// tx moves from here to mem.txs.
+	pendingSendersMtx sync.Mutex
+	pendingSenders    map[string]map[p2p.ID]struct{}
+
 	// Keep a cache of already-seen txs.
 	// This reduces the pressure on the proxyApp.
 	cache txCache
@@ -87,6 +109,8 @@ type Mempool struct {
 	// A log of mempool txs
 	wal *auto.AutoFile
 
+	onExpired ExpiredTxCallback
+
 	logger log.Logger
 
 	metrics *Metrics
@@ -103,16 +127,17 @@ func NewMempool(
 	options ...MempoolOption,
 ) *Mempool {
 	mempool := &Mempool{
-		config:        config,
-		proxyAppConn:  proxyAppConn,
-		txs:           clist.New(),
-		counter:       0,
-		height:        height,
-		rechecking:    0,
-		recheckCursor: nil,
-		recheckEnd:    nil,
-		logger:        log.NewNopLogger(),
-		metrics:       NopMetrics(),
+		config:         config,
+		proxyAppConn:   proxyAppConn,
+		txs:            clist.New(),
+		counter:        0,
+		height:         height,
+		rechecking:     0,
+		recheckCursor:  nil,
+		recheckEnd:     nil,
+		pendingSenders: make(map[string]map[p2p.ID]struct{}),
+		logger:         log.NewNopLogger(),
+		metrics:        NopMetrics(),
 	}
 	if config.CacheSize > 0 {
 		mempool.cache = newMapTxCache(config.CacheSize)
@@ -143,6 +168,11 @@ func WithMetrics(metrics *Metrics) MempoolOption {
 	return func(mem *Mempool) { mem.metrics = metrics }
 }
 
+// WithExpiredTxCallback sets the callback invoked for each expired tx.
+func WithExpiredTxCallback(cb ExpiredTxCallback) MempoolOption {
+	return func(mem *Mempool) { mem.onExpired = cb }
+}
+
 // CloseWAL closes and discards the underlying WAL file.
 // Any further writes will not be relayed to disk.
 func (mem *Mempool) CloseWAL() bool {
@@ -193,6 +223,11 @@ func (mem *Mempool) Size() int {
 	return mem.txs.Len()
 }
 
+// TxsBytes returns the total size of all transactions in the mempool.
+func (mem *Mempool) TxsBytes() int64 {
+	return atomic.LoadInt64(&mem.txsBytes)
+}
+
 // Flushes the mempool connection to ensure async resCb calls are done e.g.
 // from CheckTx.
 func (mem *Mempool) FlushAppConn() error {
@@ -206,9 +241,12 @@ func (mem *Mempool) Flush() {
 
 	mem.cache.Reset()
 
+	mem.pendingSendersMtx.Lock()
+	mem.pendingSenders = make(map[string]map[p2p.ID]struct{})
+	mem.pendingSendersMtx.Unlock()
+
 	for e := mem.txs.Front(); e != nil; e = e.Next() {
-		mem.txs.Remove(e)
-		e.DetachPrev()
+		mem.removeTx(e)
 	}
 }
 
@@ -232,15 +270,25 @@ func (mem *Mempool) TxsWaitChan() <-chan struct{} {
 //     It gets called from another goroutine.
 // CONTRACT: Either cb will get called, or err returned.
 func (mem *Mempool) CheckTx(tx types.Tx, cb func(*abci.Response)) (err error) {
+	return mem.CheckTxWithInfo(tx, cb, TxInfo{})
+}
+
+// CheckTxWithInfo performs the same operation as CheckTx, but with extra
+// meta data about the tx.
+func (mem *Mempool) CheckTxWithInfo(tx types.Tx, cb func(*abci.Response), txInfo TxInfo) (err error) {
 	mem.proxyMtx.Lock()
 	defer mem.proxyMtx.Unlock()
 
-	if mem.Size() >= mem.config.Size {
+	if mem.isFull(len(tx)) {
 		return ErrMempoolIsFull
 	}
 
 	// CACHE
 	if !mem.cache.Push(tx) {
+		// Record a new sender for a tx we already have, so it isn't sent back.
+		if txInfo.PeerID != "" {
+			mem.addSender(tx, txInfo.PeerID)
+		}
 		return ErrTxInCache
 	}
 	// END CACHE
@@ -263,6 +311,17 @@ func (mem *Mempool) CheckTx(tx types.Tx, cb func(*abci.Response)) (err error) {
 	if err = mem.proxyAppConn.Error(); err != nil {
 		return err
 	}
+	if txInfo.PeerID != "" {
+		mem.pendingSendersMtx.Lock()
+		senders, ok := mem.pendingSenders[txKey(tx)]
+		if !ok {
+			// (with the cache disabled, the tx may already be pending)
+			senders = make(map[p2p.ID]struct{})
+			mem.pendingSenders[txKey(tx)] = senders
+		}
+		senders[txInfo.PeerID] = struct{}{}
+		mem.pendingSendersMtx.Unlock()
+	}
 	reqRes := mem.proxyAppConn.CheckTxAsync(tx)
 	if cb != nil {
 		reqRes.SetCallback(cb)
@@ -285,28 +344,61 @@ func (mem *Mempool) resCbNormal(req *abci.Request, res *abci.Response) {
 	switch r := res.Value.(type) {
 	case *abci.Response_CheckTx:
 		tx := req.GetCheckTx().Tx
-		if r.CheckTx.Code == abci.CodeTypeOK {
-			mem.counter++
-			memTx := &mempoolTx{
-				counter: mem.counter,
-				height:  mem.height,
-				tx:      tx,
-			}
-			mem.txs.PushBack(memTx)
-			mem.logger.Info("Added good transaction", "tx", TxID(tx), "res", r, "total", mem.Size())
-			mem.notifyTxsAvailable()
-		} else {
+		mem.pendingSendersMtx.Lock()
+		senders := mem.pendingSenders[txKey(tx)]
+		delete(mem.pendingSenders, txKey(tx))
+		if r.CheckTx.Code != abci.CodeTypeOK {
+			mem.pendingSendersMtx.Unlock()
+
 			// ignore bad transaction
 			mem.logger.Info("Rejected bad transaction", "tx", TxID(tx), "res", r)
 
 			// remove from cache (it might be good later)
 			mem.cache.Remove(tx)
+		} else if e, ok := mem.txsMap.Load(txKey(tx)); ok {
+			// already in the mempool, eg. sent again with the cache disabled
+			memTx := e.(*clist.CElement).Value.(*mempoolTx)
+			for peerID := range senders {
+				memTx.senders.Store(peerID, struct{}{})
+			}
+			mem.pendingSendersMtx.Unlock()
+			mem.logger.Debug("Transaction already in mempool", "tx", TxID(tx))
+		} else {
+			mem.counter++
+			memTx := &mempoolTx{
+				counter:   mem.counter,
+				height:    mem.height,
+				timestamp: time.Now(),
+				tx:        tx,
+			}
+			for peerID := range senders {
+				memTx.senders.Store(peerID, struct{}{})
+			}
+			e := mem.txs.PushBack(memTx)
+			mem.txsMap.Store(txKey(tx), e)
+			mem.pendingSendersMtx.Unlock()
+			atomic.AddInt64(&mem.txsBytes, int64(len(tx)))
+			mem.logger.Info("Added good transaction", "tx", TxID(tx), "res", r, "total", mem.Size())
+			mem.notifyTxsAvailable()
 		}
 	default:
 		// ignore other messages
 	}
 }
 
+// addSender records that peerID also sent tx, if tx is in the mempool or
+// still being checked by the app.
+func (mem *Mempool) addSender(tx types.Tx, peerID p2p.ID) {
+	mem.pendingSendersMtx.Lock()
+	defer mem.pendingSendersMtx.Unlock()
+	key := txKey(tx)
+	if e, ok := mem.txsMap.Load(key); ok {
+		e.(*clist.CElement).Value.(*mempoolTx).senders.Store(peerID, struct{}{})
+	} else if senders, ok := mem.pendingSenders[key]; ok {
+		senders[peerID] = struct{}{}
+	}
+}
+
 func (mem *Mempool) resCbRecheck(req *abci.Request, res *abci.Response) {
 	switch r := res.Value.(type) {
 	case *abci.Response_CheckTx:
@@ -319,8 +411,7 @@ func (mem *Mempool) resCbRecheck(req *abci.Request, res *abci.Response) {
 			// Good, nothing to do.
 		} else {
 			// Tx became invalidated due to newly committed block.
-			mem.txs.Remove(mem.recheckCursor)
-			mem.recheckCursor.DetachPrev()
+			mem.removeTx(mem.recheckCursor)
 
 			// remove from cache (it might be good later)
 			mem.cache.Remove(req.GetCheckTx().Tx)
@@ -410,8 +501,9 @@ func (mem *Mempool) Update(height int64, txs types.Txs) error {
 	mem.height = height
 	mem.notifiedTxsAvailable = false
 
-	// Remove transactions that are already in txs.
-	goodTxs := mem.filterTxs(txsMap)
+	// Remove transactions that are already in txs, then those that expired.
+	mem.filterTxs(txsMap)
+	goodTxs := mem.expireTxs(height, time.Now())
 	// Recheck mempool txs if any txs were committed in the block
 	// NOTE/XXX: in some apps a tx could be invalidated due to EndBlock,
 	//	so we really still do need to recheck, but this is for debugging
@@ -426,17 +518,33 @@ func (mem *Mempool) Update(height int64, txs types.Txs) error {
 	return nil
 }
 
-func (mem *Mempool) filterTxs(blockTxsMap map[string]struct{}) []types.Tx {
-	goodTxs := make([]types.Tx, 0, mem.txs.Len())
+func (mem *Mempool) filterTxs(blockTxsMap map[string]struct{}) {
 	for e := mem.txs.Front(); e != nil; e = e.Next() {
 		memTx := e.Value.(*mempoolTx)
 		// Remove the tx if it's alredy in a block.
 		if _, ok := blockTxsMap[string(memTx.tx)]; ok {
-			// remove from clist
-			mem.txs.Remove(e)
-			e.DetachPrev()
+			mem.removeTx(e)
 
 			// NOTE: we don't remove committed txs from the cache.
+		}
+	}
+}
+
+// expireTxs removes the txs that outlived TTLNumBlocks or TTLDuration and
+// returns the ones left.
+func (mem *Mempool) expireTxs(height int64, now time.Time) []types.Tx {
+	goodTxs := make([]types.Tx, 0, mem.txs.Len())
+	for e := mem.txs.Front(); e != nil; e = e.Next() {
+		memTx := e.Value.(*mempoolTx)
+		if mem.isExpired(memTx, height, now) {
+			mem.removeTx(e)
+
+			// remove from cache so it can be resubmitted
+			mem.cache.Remove(memTx.tx)
+			mem.logger.Info("Expired transaction", "tx", TxID(memTx.tx), "height", memTx.Height())
+			if mem.onExpired != nil {
+				mem.onExpired(memTx.tx)
+			}
 			continue
 		}
 		// Good tx!
@@ -445,6 +553,43 @@ func (mem *Mempool) filterTxs(blockTxsMap map[string]struct{}) []types.Tx {
 	return goodTxs
 }
 
+func (mem *Mempool) isExpired(memTx *mempoolTx, height int64, now time.Time) bool {
+	if mem.config.TTLNumBlocks > 0 && height-memTx.Height() > mem.config.TTLNumBlocks {
+		return true
+	}
+	if mem.config.TTLDuration > 0 && now.Sub(memTx.timestamp) > mem.config.TTLDuration {
+		return true
+	}
+	return false
+}
+
+// isFull reports whether adding a tx of txSize bytes would exceed either the
+// count or the byte limit.
+func (mem *Mempool) isFull(txSize int) bool {
+	if mem.Size() >= mem.config.Size {
+		return true
+	}
+	return mem.config.MaxTxsBytes > 0 && mem.TxsBytes()+int64(txSize) > mem.config.MaxTxsBytes
+}
+
+// removeTx removes the element from the list and updates the byte count.
+func (mem *Mempool) removeTx(e *clist.CElement) {
+	tx := e.Value.(*mempoolTx).tx
+	mem.txs.Remove(e)
+	e.DetachPrev()
+	mem.pendingSendersMtx.Lock()
+	if stored, ok := mem.txsMap.Load(txKey(tx)); ok && stored.(*clist.CElement) == e {
+		mem.txsMap.Delete(txKey(tx))
+	}
+	mem.pendingSendersMtx.Unlock()
+	atomic.AddInt64(&mem.txsBytes, -int64(len(tx)))
+}
+
+// txKey is the key of tx in txsMap and pendingSenders.
+func txKey(tx types.Tx) string {
+	return string(tx.Hash())
+}
+
 // NOTE: pass in goodTxs because mem.txs can mutate concurrently.
 func (mem *Mempool) recheckTxs(goodTxs []types.Tx) {
 	if len(goodTxs) == 0 {
@@ -466,9 +611,19 @@ func (mem *Mempool) recheckTxs(goodTxs []types.Tx) {
 
 // mempoolTx is a transaction that successfully ran
 type mempoolTx struct {
-	counter int64    // a simple incrementing counter
-	height  int64    // height that this tx had been validated in
-	tx      types.Tx //
+	counter   int64     // a simple incrementing counter
+	height    int64     // height that this tx had been validated in
+	timestamp time.Time // time that this tx was added to the mempool
+	tx        types.Tx  //
+
+	// ids of peers who've sent us this tx (as a map for quick lookups)
+	senders sync.Map
+}
+
+// isSender reports whether the peer sent us this tx.
+func (memTx *mempoolTx) isSender(peerID p2p.ID) bool {
+	_, ok := memTx.senders.Load(peerID)
+	return ok
 }
 
 // Height returns the height for this transaction
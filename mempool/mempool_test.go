@@ -18,6 +18,7 @@ import (
 	"github.com/tendermint/tendermint/libs/log"
 
 	cfg "github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/p2p"
 	"github.com/tendermint/tendermint/proxy"
 	"github.com/tendermint/tendermint/types"
 
@@ -224,6 +225,130 @@ func TestSerialReap(t *testing.T) {
 	reapCheck(600)
 }
 
+func TestMempoolMaxTxsBytes(t *testing.T) {
+	app := kvstore.NewKVStoreApplication()
+	cc := proxy.NewLocalClientCreator(app)
+	mempool := newMempoolWithApp(cc)
+	mempool.config.MaxTxsBytes = 100
+
+	// 5 txs of 20 bytes fill the mempool
+	txs := checkTxs(t, mempool, 5)
+	require.EqualValues(t, 100, mempool.TxsBytes())
+	err := mempool.CheckTx(types.Tx(cmn.RandBytes(20)), nil)
+	require.Equal(t, ErrMempoolIsFull, err)
+	// a smaller tx doesn't fit either
+	err = mempool.CheckTx(types.Tx(cmn.RandBytes(1)), nil)
+	require.Equal(t, ErrMempoolIsFull, err)
+
+	// committing a tx frees its bytes
+	require.NoError(t, mempool.Update(1, txs[:1]))
+	require.EqualValues(t, 80, mempool.TxsBytes())
+	checkTxs(t, mempool, 1)
+	require.EqualValues(t, 100, mempool.TxsBytes())
+
+	mempool.Flush()
+	require.EqualValues(t, 0, mempool.TxsBytes())
+}
+
+func TestMempoolPendingSenders(t *testing.T) {
+	app := kvstore.NewKVStoreApplication()
+	cc := proxy.NewLocalClientCreator(app)
+	mempool := newMempoolWithApp(cc)
+
+	// tx from peer a is still being checked by the app when b sends it
+	tx := types.Tx("pending")
+	mempool.cache.Push(tx)
+	mempool.pendingSenders[txKey(tx)] = map[p2p.ID]struct{}{"a": {}}
+	err := mempool.CheckTxWithInfo(tx, nil, TxInfo{PeerID: "b"})
+	require.Equal(t, ErrTxInCache, err)
+
+	mempool.resCb(abci.ToRequestCheckTx(tx), abci.ToResponseCheckTx(abci.ResponseCheckTx{Code: abci.CodeTypeOK}))
+	require.Equal(t, 1, mempool.Size())
+	memTx := mempool.TxsFront().Value.(*mempoolTx)
+	require.True(t, memTx.isSender("a"))
+	require.True(t, memTx.isSender("b"))
+	require.Empty(t, mempool.pendingSenders)
+
+	// a seen tx that is neither pending nor in the mempool isn't tracked
+	seen := types.Tx("seen")
+	mempool.cache.Push(seen)
+	err = mempool.CheckTxWithInfo(seen, nil, TxInfo{PeerID: "b"})
+	require.Equal(t, ErrTxInCache, err)
+	require.Empty(t, mempool.pendingSenders)
+
+	// flushing forgets the pending senders
+	mempool.pendingSenders[txKey(seen)] = map[p2p.ID]struct{}{"a": {}}
+	mempool.Flush()
+	require.Empty(t, mempool.pendingSenders)
+}
+
+func TestMempoolSendersWithoutCache(t *testing.T) {
+	app := kvstore.NewKVStoreApplication()
+	cc := proxy.NewLocalClientCreator(app)
+	mempool := newMempoolWithApp(cc)
+	mempool.cache = nopTxCache{} // as with mempool.cache_size = 0
+
+	// the same tx from a and b is kept once, with both senders
+	tx := types.Tx("dup")
+	require.NoError(t, mempool.CheckTxWithInfo(tx, nil, TxInfo{PeerID: "a"}))
+	require.NoError(t, mempool.CheckTxWithInfo(tx, nil, TxInfo{PeerID: "b"}))
+	require.Equal(t, 1, mempool.Size())
+	first := mempool.TxsFront()
+	require.True(t, first.Value.(*mempoolTx).isSender("a"))
+	require.True(t, first.Value.(*mempoolTx).isSender("b"))
+
+	// removing a stale copy keeps the entry of the one in txsMap
+	second := mempool.txs.PushBack(&mempoolTx{tx: tx})
+	mempool.txsMap.Store(txKey(tx), second)
+	mempool.removeTx(first)
+	e, ok := mempool.txsMap.Load(txKey(tx))
+	require.True(t, ok)
+	require.Equal(t, second, e)
+
+	require.NoError(t, mempool.CheckTxWithInfo(tx, nil, TxInfo{PeerID: "c"}))
+	require.Equal(t, 1, mempool.Size())
+	require.True(t, second.Value.(*mempoolTx).isSender("c"))
+}
+
+func TestMempoolTTLNumBlocks(t *testing.T) {
+	app := kvstore.NewKVStoreApplication()
+	cc := proxy.NewLocalClientCreator(app)
+	mempool := newMempoolWithApp(cc)
+	mempool.config.TTLNumBlocks = 2
+	mempool.config.MaxTxsBytes = 60
+	var expired types.Txs
+	WithExpiredTxCallback(func(tx types.Tx) { expired = append(expired, tx) })(mempool)
+
+	oldTxs := checkTxs(t, mempool, 2)
+	require.NoError(t, mempool.Update(1, nil))
+	newTxs := checkTxs(t, mempool, 1)
+	require.Equal(t, ErrMempoolIsFull, mempool.CheckTx(types.Tx(cmn.RandBytes(20)), nil))
+
+	// at height 3 the txs added at height 0 are too old
+	require.NoError(t, mempool.Update(3, nil))
+	require.Equal(t, newTxs, mempool.Reap(-1))
+	require.Equal(t, oldTxs, expired)
+	require.EqualValues(t, 20, mempool.TxsBytes())
+
+	// expired txs are dropped from the cache, so they can be resubmitted
+	require.NoError(t, mempool.CheckTx(oldTxs[0], nil))
+	require.Equal(t, 2, mempool.Size())
+}
+
+func TestMempoolTTLDuration(t *testing.T) {
+	app := kvstore.NewKVStoreApplication()
+	cc := proxy.NewLocalClientCreator(app)
+	mempool := newMempoolWithApp(cc)
+	mempool.config.TTLDuration = 50 * time.Millisecond
+
+	checkTxs(t, mempool, 3)
+	time.Sleep(100 * time.Millisecond)
+	newTxs := checkTxs(t, mempool, 1)
+
+	require.NoError(t, mempool.Update(1, nil))
+	require.Equal(t, newTxs, mempool.Reap(-1))
+}
+
 func TestMempoolCloseWAL(t *testing.T) {
 	// 1. Create the temporary directory for mempool and WAL testing.
 	rootDir, err := ioutil.TempDir("", "mempool-test")
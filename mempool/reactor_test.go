@@ -17,6 +17,7 @@ import (
 
 	cfg "github.com/tendermint/tendermint/config"
 	"github.com/tendermint/tendermint/p2p"
+	"github.com/tendermint/tendermint/p2p/dummy"
 	"github.com/tendermint/tendermint/proxy"
 	"github.com/tendermint/tendermint/types"
 )
@@ -155,3 +156,72 @@ func TestBroadcastTxForPeerStopsWhenReactorStops(t *testing.T) {
 	// i.e. broadcastTxRoutine finishes when reactor is stopped
 	leaktest.CheckTimeout(t, 10*time.Second)()
 }
+
+// recordingPeer is a dummy peer which records every tx sent to it.
+type recordingPeer struct {
+	p2p.Peer
+	id  p2p.ID
+	txs chan types.Tx
+}
+
+func newRecordingPeer(id p2p.ID) *recordingPeer {
+	return &recordingPeer{Peer: dummy.NewPeer(), id: id, txs: make(chan types.Tx, 10)}
+}
+
+func (p *recordingPeer) ID() p2p.ID { return p.id }
+
+func (p *recordingPeer) Send(chID byte, msgBytes []byte) bool {
+	msg, err := decodeMsg(msgBytes)
+	if err != nil {
+		panic(err)
+	}
+	p.txs <- msg.(*TxMessage).Tx
+	return true
+}
+
+func TestReactorNoEchoToSender(t *testing.T) {
+	config := cfg.TestConfig()
+	app := kvstore.NewKVStoreApplication()
+	cc := proxy.NewLocalClientCreator(app)
+	reactor := NewMempoolReactor(config.Mempool, newMempoolWithApp(cc))
+	reactor.SetLogger(log.TestingLogger())
+
+	peerA, peerB := newRecordingPeer("a"), newRecordingPeer("b")
+	for _, peer := range []*recordingPeer{peerA, peerB} {
+		peer.Start()
+		defer peer.Stop()
+		reactor.AddPeer(peer)
+	}
+
+	// peer A sends a tx, then again; only peer B gets it
+	tx := types.Tx("from-a")
+	msgBytes := cdc.MustMarshalBinaryBare(&TxMessage{Tx: tx})
+	reactor.Receive(MempoolChannel, peerA, msgBytes)
+	reactor.Receive(MempoolChannel, peerA, msgBytes)
+	select {
+	case got := <-peerB.txs:
+		assert.Equal(t, tx, got)
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for tx on peer B")
+	}
+
+	// peer B relays it back to us, and a tx from the RPC goes to both
+	reactor.Receive(MempoolChannel, peerB, msgBytes)
+	rpcTx := types.Tx("from-rpc")
+	assert.NoError(t, reactor.BroadcastTx(rpcTx, nil))
+	for _, peer := range []*recordingPeer{peerA, peerB} {
+		select {
+		case got := <-peer.txs:
+			assert.Equal(t, rpcTx, got, "peer %v", peer.id)
+		case <-time.After(time.Second):
+			t.Fatalf("Timed out waiting for rpc tx on peer %v", peer.id)
+		}
+	}
+	select {
+	case got := <-peerA.txs:
+		t.Fatalf("peer A got %v", got)
+	case got := <-peerB.txs:
+		t.Fatalf("peer B got %v", got)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
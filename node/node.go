@@ -133,6 +133,7 @@ type Node struct {
 	rpcListeners     []net.Listener         // rpc servers
 	txIndexer        txindex.TxIndexer
 	indexerService   *txindex.IndexerService
+	pruner           *bc.Pruner // nil unless retain_blocks is set
 	prometheusSrv    *http.Server
 }
 
@@ -231,6 +232,9 @@ func NewNode(config *cfg.Config,
 
 	csMetrics, p2pMetrics, memplMetrics := metricsProvider()
 
+	eventBus := types.NewEventBus()
+	eventBus.SetLogger(logger.With("module", "events"))
+
 	// Make MempoolReactor
 	mempoolLogger := logger.With("module", "mempool")
 	mempool := mempl.NewMempool(
@@ -238,6 +242,9 @@ func NewNode(config *cfg.Config,
 		proxyApp.Mempool(),
 		state.LastBlockHeight,
 		mempl.WithMetrics(memplMetrics),
+		mempl.WithExpiredTxCallback(func(tx types.Tx) {
+			eventBus.PublishEventExpiredTx(types.EventDataExpiredTx{Tx: tx})
+		}),
 	)
 	mempool.SetLogger(mempoolLogger)
 	mempool.InitWAL() // no need to have the mempool wal during tests
@@ -348,9 +355,6 @@ func NewNode(config *cfg.Config,
 		})
 	}
 
-	eventBus := types.NewEventBus()
-	eventBus.SetLogger(logger.With("module", "events"))
-
 	// services which will be publishing and/or subscribing for messages (events)
 	// consensusReactor will set it on consensusState and blockExecutor
 	consensusReactor.SetEventBus(eventBus)
@@ -377,6 +381,12 @@ func NewNode(config *cfg.Config,
 	indexerService := txindex.NewIndexerService(txIndexer, eventBus)
 	indexerService.SetLogger(logger.With("module", "txindex"))
 
+	var pruner *bc.Pruner
+	if config.RetainBlocks > 0 {
+		pruner = bc.NewPruner(blockStore, eventBus, config.RetainBlocks)
+		pruner.SetLogger(logger.With("module", "blockchain"))
+	}
+
 	// run the profile server
 	profileHost := config.ProfListenAddress
 	if profileHost != "" {
@@ -403,6 +413,7 @@ func NewNode(config *cfg.Config,
 		proxyApp:         proxyApp,
 		txIndexer:        txIndexer,
 		indexerService:   indexerService,
+		pruner:           pruner,
 		eventBus:         eventBus,
 	}
 	node.BaseService = *cmn.NewBaseService(logger, "Node", node)
@@ -471,6 +482,17 @@ func (n *Node) OnStart() error {
 		}
 	}
 
+	// start block pruner
+	if n.pruner != nil {
+		if err := n.pruner.Start(); err != nil {
+			return err
+		}
+	}
+
+	// index blocks saved before the block hash index existed, in the
+	// background so a long chain doesn't hold up the start
+	go n.indexBlockHashes()
+
 	// start tx indexer
 	return n.indexerService.Start()
 }
@@ -484,9 +506,12 @@ func (n *Node) OnStop() {
 	// first stop the non-reactor services
 	n.eventBus.Stop()
 	n.indexerService.Stop()
+	if n.pruner != nil {
+		n.pruner.Stop()
+	}
 
-	// now stop the reactors
-	// TODO: gracefully disconnect from peers.
+	// now stop the reactors. The switch flushes what's queued for each peer
+	// before disconnecting.
 	n.sw.Stop()
 
 	// finally stop the listeners / external services
@@ -511,6 +536,13 @@ func (n *Node) OnStop() {
 	}
 }
 
+func (n *Node) indexBlockHashes() {
+	indexed := n.blockStore.IndexBlockHashes(n.Quit())
+	if indexed > 0 {
+		n.Logger.Info("Indexed block hashes", "blocks", indexed)
+	}
+}
+
 // RunForever waits for an interrupt signal and stops the node.
 func (n *Node) RunForever() {
 	// Sleep forever and then...
@@ -543,6 +575,7 @@ func (n *Node) ConfigureRPC() {
 	rpccore.SetConsensusReactor(n.consensusReactor)
 	rpccore.SetEventBus(n.eventBus)
 	rpccore.SetLogger(n.Logger.With("module", "rpc"))
+	rpccore.SetConfig(*n.config.RPC)
 }
 
 func (n *Node) startRPC() ([]net.Listener, error) {
@@ -84,9 +84,15 @@ type MConnection struct {
 	errored       uint32
 	config        MConnConfig
 
-	quit       chan struct{}
-	flushTimer *cmn.ThrottleTimer // flush writes as necessary but throttled.
-	pingTimer  *cmn.RepeatTimer   // send pings periodically
+	quit            chan struct{}
+	doneSendRoutine chan struct{}
+	flushTimer      *cmn.ThrottleTimer // flush writes as necessary but throttled.
+
+	// if non-zero, OnStop writes out queued messages for up to this long
+	// before closing the conn. See FlushStop.
+	flushTimeout time.Duration
+
+	pingTimer *cmn.RepeatTimer // send pings periodically
 
 	// close conn if pong is not received in pongTimeout
 	pongTimer     *time.Timer
@@ -191,6 +197,7 @@ func (c *MConnection) OnStart() error {
 		return err
 	}
 	c.quit = make(chan struct{})
+	c.doneSendRoutine = make(chan struct{})
 	c.flushTimer = cmn.NewThrottleTimer("flush", c.config.FlushThrottle)
 	c.pingTimer = cmn.NewRepeatTimer("ping", c.config.PingInterval)
 	c.pongTimeoutCh = make(chan bool, 1)
@@ -209,6 +216,9 @@ func (c *MConnection) OnStop() {
 	if c.quit != nil {
 		close(c.quit)
 	}
+	if c.flushTimeout > 0 {
+		c.flushPending(c.flushTimeout)
+	}
 	c.conn.Close() // nolint: errcheck
 
 	// We can't close pong safely here because
@@ -217,6 +227,37 @@ func (c *MConnection) OnStop() {
 	// we close it @ recvRoutine.
 }
 
+// FlushStop stops the connection like Stop, but first writes out the
+// messages already queued on its channels, giving up after timeout.
+func (c *MConnection) FlushStop(timeout time.Duration) error {
+	c.flushTimeout = timeout
+	return c.Stop()
+}
+
+// flushPending sends whatever is left in the channels' send queues. Must only
+// be called once quit is closed.
+func (c *MConnection) flushPending(timeout time.Duration) {
+	deadline := time.Now().Add(timeout)
+
+	// wait for sendRoutine to exit, so we don't race on the send queues
+	select {
+	case <-c.doneSendRoutine:
+	case <-time.After(timeout):
+		return
+	}
+
+	if err := c.conn.SetWriteDeadline(deadline); err != nil {
+		c.Logger.Error("Failed to set write deadline", "err", err)
+	}
+	for !c.sendSomePacketMsgs() {
+		if time.Now().After(deadline) {
+			c.Logger.Info("Gave up flushing pending messages", "conn", c)
+			return
+		}
+	}
+	c.flush()
+}
+
 func (c *MConnection) String() string {
 	return fmt.Sprintf("MConn{%v}", c.conn.RemoteAddr())
 }
@@ -319,6 +360,7 @@ func (c *MConnection) CanSend(chID byte) bool {
 
 // sendRoutine polls for packets to send from channels.
 func (c *MConnection) sendRoutine() {
+	defer close(c.doneSendRoutine)
 	defer c._recover()
 
 FOR_LOOP:
@@ -596,10 +638,21 @@ func (c *MConnection) Status() ConnectionStatus {
 //-----------------------------------------------------------------------------
 
 type ChannelDescriptor struct {
-	ID                  byte
-	Priority            int
-	SendQueueCapacity   int
-	RecvBufferCapacity  int
+	ID       byte
+	Priority int
+
+	// SendQueueCapacity is the number of messages which can be queued for
+	// sending. TrySend fails and Send blocks (up to defaultSendTimeout)
+	// once the queue is full.
+	SendQueueCapacity int
+
+	// RecvBufferCapacity is the size of the receive buffer kept between
+	// messages. Bigger messages are still accepted (up to
+	// RecvMessageCapacity), but their buffer is released afterwards.
+	RecvBufferCapacity int
+
+	// RecvMessageCapacity is the max size of a received message. Bigger
+	// messages are an error and stop the connection.
 	RecvMessageCapacity int
 }
 
@@ -685,7 +738,7 @@ func (ch *Channel) loadSendQueueSize() (size int) {
 // Goroutine-safe
 // Use only as a heuristic.
 func (ch *Channel) canSend() bool {
-	return ch.loadSendQueueSize() < defaultSendQueueCapacity
+	return ch.loadSendQueueSize() < ch.desc.SendQueueCapacity
 }
 
 // Returns true if any PacketMsgs are pending to be sent.
@@ -741,11 +794,14 @@ func (ch *Channel) recvPacketMsg(packet PacketMsg) ([]byte, error) {
 	if packet.EOF == byte(0x01) {
 		msgBytes := ch.recving
 
-		// clear the slice without re-allocating.
-		// http://stackoverflow.com/questions/16971741/how-do-you-clear-a-slice-in-go
-		//   suggests this could be a memory leak, but we might as well keep the memory for the channel until it closes,
-		//	at which point the recving slice stops being used and should be garbage collected
-		ch.recving = ch.recving[:0] // make([]byte, 0, ch.desc.RecvBufferCapacity)
+		// clear the slice without re-allocating, unless a big message grew it
+		// past RecvBufferCapacity, in which case we don't keep the memory
+		// around for the lifetime of the channel.
+		if cap(ch.recving) > ch.desc.RecvBufferCapacity {
+			ch.recving = make([]byte, 0, ch.desc.RecvBufferCapacity)
+		} else {
+			ch.recving = ch.recving[:0]
+		}
 		return msgBytes, nil
 	}
 	return nil, nil
@@ -2,6 +2,7 @@ package conn
 
 import (
 	"bytes"
+	"math/rand"
 	"net"
 	"testing"
 	"time"
@@ -11,6 +12,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	amino "github.com/tendermint/go-amino"
+	cmn "github.com/tendermint/tendermint/libs/common"
 	"github.com/tendermint/tendermint/libs/log"
 )
 
@@ -67,6 +69,64 @@ func TestMConnectionSend(t *testing.T) {
 	assert.False(t, mconn.Send(0x05, []byte("Absorbing Man")), "Send should return false because channel is unknown")
 }
 
+func TestMConnectionFlushStop(t *testing.T) {
+	server, client := NetPipe()
+	defer server.Close() // nolint: errcheck
+	defer client.Close() // nolint: errcheck
+
+	receivedCh := make(chan []byte, 100)
+	errorsCh := make(chan interface{}, 1)
+	onReceive := func(chID byte, msgBytes []byte) {
+		// msgBytes is reused by the conn
+		receivedCh <- append([]byte(nil), msgBytes...)
+	}
+	onError := func(r interface{}) {
+		errorsCh <- r
+	}
+	// the stopping side doesn't answer pings, so don't send any meanwhile
+	cfg := DefaultMConnConfig()
+	chDescs := []*ChannelDescriptor{&ChannelDescriptor{ID: 0x01, Priority: 1, SendQueueCapacity: 1}}
+	mconn1 := NewMConnectionWithConfig(client, chDescs, onReceive, onError, cfg)
+	mconn1.SetLogger(log.TestingLogger())
+	err := mconn1.Start()
+	require.Nil(t, err)
+	defer mconn1.Stop()
+
+	// throttle the sender, so most messages are still queued at the stop
+	cfg.SendRate = 5000
+	chDescs = []*ChannelDescriptor{&ChannelDescriptor{ID: 0x01, Priority: 1, SendQueueCapacity: 100}}
+	mconn2 := NewMConnectionWithConfig(server, chDescs, func(byte, []byte) {}, func(interface{}) {}, cfg)
+	mconn2.SetLogger(log.TestingLogger())
+	err = mconn2.Start()
+	require.Nil(t, err)
+
+	const numMsgs = 50
+	msg := func(i int) []byte {
+		return append([]byte{byte(i)}, make([]byte, 99)...)
+	}
+	for i := 0; i < numMsgs; i++ {
+		require.True(t, mconn2.Send(0x01, msg(i)))
+	}
+	require.True(t, len(receivedCh) < numMsgs/2, "the sender should still have queued messages")
+	require.Nil(t, mconn2.FlushStop(10*time.Second))
+	assert.False(t, mconn2.IsRunning())
+
+	// everything queued before the stop arrives, then the conn is closed
+	for i := 0; i < numMsgs; i++ {
+		select {
+		case m := <-receivedCh:
+			assert.Equal(t, msg(i), m)
+		case <-time.After(time.Second):
+			t.Fatalf("only received %d of %d messages", i, numMsgs)
+		}
+	}
+	select {
+	case <-errorsCh:
+	case <-time.After(time.Second):
+		t.Fatal("expected the remote side to notice the closed connection")
+	}
+}
+
 func TestMConnectionReceive(t *testing.T) {
 	server, client := NetPipe()
 	defer server.Close() // nolint: errcheck
@@ -465,6 +525,60 @@ func TestMConnectionReadErrorUnknownMsgType(t *testing.T) {
 	assert.True(t, expectSend(chOnErr), "unknown msg type")
 }
 
+func TestMConnectionReadCorruptedFrames(t *testing.T) {
+	valid := cdc.MustMarshalBinary(PacketMsg{ChannelID: 0x01, EOF: 1, Bytes: []byte("Ant-Man")})
+
+	for i := 0; i < 50; i++ {
+		rng := rand.New(rand.NewSource(int64(i)))
+
+		// either garbage or a valid packet with a few flipped bytes
+		var bz []byte
+		if i%2 == 0 {
+			bz = make([]byte, 1+rng.Intn(2048))
+			rng.Read(bz)
+		} else {
+			bz = append([]byte(nil), valid...)
+			for j := 0; j < 1+rng.Intn(3); j++ {
+				bz[rng.Intn(len(bz))] ^= byte(1 + rng.Intn(255))
+			}
+		}
+
+		server, client := NetPipe()
+		errCh := make(chan interface{}, 1)
+		onReceive := func(chID byte, msgBytes []byte) {
+			assert.EqualValues(t, 0x01, chID, "case %d", i)
+		}
+		onError := func(r interface{}) {
+			errCh <- r
+		}
+		mconn := createMConnectionWithCallbacks(server, onReceive, onError)
+		require.Nil(t, mconn.Start())
+
+		// closing the client afterwards ends incomplete frames with an EOF,
+		// so every case finishes with an error
+		go func() {
+			client.Write(bz) // nolint: errcheck
+			client.Close()   // nolint: errcheck
+		}()
+
+		select {
+		case r := <-errCh:
+			// a panic in the read path is recovered and wrapped in a cmn.Error,
+			// while the expected decode, EOF and unknown channel/type errors
+			// are passed as is
+			_, recovered := r.(cmn.Error)
+			assert.False(t, recovered, "case %d: %#v", i, r)
+			_, isErr := r.(error)
+			assert.True(t, isErr, "case %d: %#v", i, r)
+		case <-time.After(time.Second):
+			t.Errorf("case %d: expected the read path to fail", i)
+		}
+
+		mconn.Stop()
+		client.Close() // nolint: errcheck
+	}
+}
+
 func TestMConnectionTrySend(t *testing.T) {
 	server, client := NetPipe()
 	defer server.Close()
@@ -490,3 +604,57 @@ func TestMConnectionTrySend(t *testing.T) {
 	assert.False(t, mconn.TrySend(0x01, msg))
 	assert.Equal(t, "TrySend", <-resultCh)
 }
+
+func TestMConnectionSendQueueCapacity(t *testing.T) {
+	server, client := NetPipe()
+	defer server.Close() // nolint: errcheck
+	defer client.Close() // nolint: errcheck
+
+	const capacity = 5
+	chDescs := []*ChannelDescriptor{&ChannelDescriptor{ID: 0x01, Priority: 1, SendQueueCapacity: capacity}}
+	mconn := NewMConnectionWithConfig(client, chDescs, func(byte, []byte) {}, func(interface{}) {}, DefaultMConnConfig())
+	mconn.SetLogger(log.TestingLogger())
+	err := mconn.Start()
+	require.Nil(t, err)
+	defer mconn.Stop()
+
+	// nobody reads from the server, so the queue fills up. The send routine
+	// may have taken up to one batch of messages off the queue.
+	msg := []byte("Paste-Pot Pete")
+	sent := 0
+	for ; sent < 100; sent++ {
+		if !mconn.TrySend(0x01, msg) {
+			break
+		}
+	}
+	assert.True(t, sent >= capacity, "expected at least %d messages to be queued, got %d", capacity, sent)
+	assert.True(t, sent <= capacity+numBatchPacketMsgs, "expected the queue to be bounded, but queued %d messages", sent)
+	assert.False(t, mconn.CanSend(0x01))
+	assert.False(t, mconn.TrySend(0x01, msg))
+	assert.Equal(t, capacity, mconn.Status().Channels[0].SendQueueCapacity)
+}
+
+func TestChannelRecvBufferCapacity(t *testing.T) {
+	mconn := &MConnection{config: DefaultMConnConfig()}
+	ch := newChannel(mconn, ChannelDescriptor{ID: 0x01, Priority: 1, RecvBufferCapacity: 10, RecvMessageCapacity: 1000})
+	ch.SetLogger(log.TestingLogger())
+
+	// small messages keep the buffer
+	msgBytes, err := ch.recvPacketMsg(PacketMsg{ChannelID: 0x01, EOF: 0x01, Bytes: []byte("Vulture")})
+	require.Nil(t, err)
+	assert.Equal(t, []byte("Vulture"), msgBytes)
+	assert.Equal(t, 10, cap(ch.recving))
+
+	// big messages are received, but their buffer is not kept
+	big := cmn.RandBytes(500)
+	_, err = ch.recvPacketMsg(PacketMsg{ChannelID: 0x01, EOF: 0x00, Bytes: big[:250]})
+	require.Nil(t, err)
+	msgBytes, err = ch.recvPacketMsg(PacketMsg{ChannelID: 0x01, EOF: 0x01, Bytes: big[250:]})
+	require.Nil(t, err)
+	assert.Equal(t, big, msgBytes)
+	assert.Equal(t, 10, cap(ch.recving))
+
+	// messages over RecvMessageCapacity are an error
+	_, err = ch.recvPacketMsg(PacketMsg{ChannelID: 0x01, EOF: 0x01, Bytes: cmn.RandBytes(1001)})
+	assert.NotNil(t, err)
+}
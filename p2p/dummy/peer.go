@@ -2,6 +2,7 @@ package dummy
 
 import (
 	"net"
+	"time"
 
 	cmn "github.com/tendermint/tendermint/libs/common"
 	p2p "github.com/tendermint/tendermint/p2p"
@@ -65,6 +66,11 @@ func (p *peer) TrySend(byte, []byte) bool {
 	return true
 }
 
+// FlushStop stops the peer; there is nothing to flush.
+func (p *peer) FlushStop(time.Duration) {
+	p.Stop() // nolint: errcheck
+}
+
 // Set records value under key specified in the map.
 func (p *peer) Set(key string, value interface{}) {
 	p.kv[key] = value
@@ -34,6 +34,17 @@ func (e ErrSwitchConnectToSelf) Error() string {
 	return fmt.Sprintf("Connect to self: %v", e.Addr)
 }
 
+// ErrSwitchNetworkMismatch to be raised when a peer is on a different network
+// (chain ID).
+type ErrSwitchNetworkMismatch struct {
+	Got      string
+	Expected string
+}
+
+func (e ErrSwitchNetworkMismatch) Error() string {
+	return fmt.Sprintf("Peer is on a different network. Got %v, expected %v", e.Got, e.Expected)
+}
+
 type ErrSwitchAuthenticationFailure struct {
 	Dialed *NetAddress
 	Got    ID
@@ -100,29 +100,28 @@ func (info NodeInfo) CompatibleWith(other NodeInfo) error {
 		return fmt.Errorf("Peer is on a different major version. Got %v, expected %v", oMajor, iMajor)
 	}
 
-	// nodes must be on the same network
-	if info.Network != other.Network {
-		return fmt.Errorf("Peer is on a different network. Got %v, expected %v", other.Network, info.Network)
-	}
-
 	// if we have no channels, we're just testing
-	if len(info.Channels) == 0 {
-		return nil
-	}
-
-	// for each of our channels, check if they have it
-	found := false
-OUTER_LOOP:
-	for _, ch1 := range info.Channels {
-		for _, ch2 := range other.Channels {
-			if ch1 == ch2 {
-				found = true
-				break OUTER_LOOP // only need one
+	if len(info.Channels) > 0 {
+		// for each of our channels, check if they have it
+		found := false
+	OUTER_LOOP:
+		for _, ch1 := range info.Channels {
+			for _, ch2 := range other.Channels {
+				if ch1 == ch2 {
+					found = true
+					break OUTER_LOOP // only need one
+				}
 			}
 		}
+		if !found {
+			return fmt.Errorf("Peer has no common channels. Our channels: %v ; Peer channels: %v", info.Channels, other.Channels)
+		}
 	}
-	if !found {
-		return fmt.Errorf("Peer has no common channels. Our channels: %v ; Peer channels: %v", info.Channels, other.Channels)
+
+	// nodes must be on the same network. This is checked last, so a
+	// ErrSwitchNetworkMismatch means the peer is otherwise compatible.
+	if info.Network != other.Network {
+		return ErrSwitchNetworkMismatch{Got: other.Network, Expected: info.Network}
 	}
 	return nil
 }
@@ -31,6 +31,10 @@ type Peer interface {
 	Send(byte, []byte) bool
 	TrySend(byte, []byte) bool
 
+	// FlushStop is like Stop, but first sends what is already queued,
+	// giving up after the timeout.
+	FlushStop(timeout time.Duration)
+
 	Set(string, interface{})
 	Get(string) interface{}
 }
@@ -100,6 +104,9 @@ type peer struct {
 
 	// User data
 	Data *cmn.CMap
+
+	// if non-zero, OnStop flushes the connection. See FlushStop.
+	flushTimeout time.Duration
 }
 
 func newPeer(
@@ -232,9 +239,19 @@ func (p *peer) OnStart() error {
 // OnStop implements BaseService.
 func (p *peer) OnStop() {
 	p.BaseService.OnStop()
+	if p.flushTimeout > 0 {
+		p.mconn.FlushStop(p.flushTimeout) // nolint: errcheck
+		return
+	}
 	p.mconn.Stop() // stop everything and close the conn
 }
 
+// FlushStop implements Peer.
+func (p *peer) FlushStop(timeout time.Duration) {
+	p.flushTimeout = timeout
+	p.Stop() // nolint: errcheck
+}
+
 //---------------------------------------------------
 // Implements Peer
 
@@ -55,7 +55,7 @@ type AddrBook interface {
 	// Mark address
 	MarkGood(*p2p.NetAddress)
 	MarkAttempt(*p2p.NetAddress)
-	MarkBad(*p2p.NetAddress)
+	MarkBad(*p2p.NetAddress, float64)
 
 	IsGood(*p2p.NetAddress) bool
 
@@ -82,6 +82,8 @@ type addrBook struct {
 	filePath          string
 	routabilityStrict bool
 	key               string // random prefix for bucket placement
+	strikeHalfLife    time.Duration
+	now               func() time.Time
 
 	// accessed concurrently
 	mtx        sync.Mutex
@@ -107,6 +109,8 @@ func NewAddrBook(filePath string, routabilityStrict bool) *addrBook {
 		addrLookup:        make(map[p2p.ID]*knownAddress),
 		filePath:          filePath,
 		routabilityStrict: routabilityStrict,
+		strikeHalfLife:    defaultStrikeHalfLife,
+		now:               time.Now,
 	}
 	am.init()
 	am.BaseService = *cmn.NewBaseService(nil, "AddrBook", am)
@@ -147,6 +151,9 @@ func (a *addrBook) OnStart() error {
 // OnStop implements Service.
 func (a *addrBook) OnStop() {
 	a.BaseService.OnStop()
+	// save here rather than in saveRoutine, so the book is on disk once
+	// Stop returns
+	a.saveToFile(a.filePath)
 }
 
 func (a *addrBook) Wait() {
@@ -260,16 +267,31 @@ func (a *addrBook) PickAddress(biasTowardsNewAddrs int) *p2p.NetAddress {
 	oldCorrelation := math.Sqrt(float64(a.nOld)) * (100.0 - float64(biasTowardsNewAddrs))
 	newCorrelation := math.Sqrt(float64(a.nNew)) * float64(biasTowardsNewAddrs)
 
-	// pick a random peer from a random bucket
-	var bucket map[string]*knownAddress
 	pickFromOldBucket := (newCorrelation+oldCorrelation)*a.rand.Float64() < oldCorrelation
 	if (pickFromOldBucket && a.nOld == 0) ||
 		(!pickFromOldBucket && a.nNew == 0) {
 		return nil
 	}
+
+	// keep the picked address with a chance depending on its strikes, so
+	// penalized addresses are picked less often (or not at all)
+	now := a.now()
+	for i := 0; i < maxPickAttempts; i++ {
+		ka := a.pickRandom(pickFromOldBucket)
+		if a.rand.Float64() < ka.pickWeight(now, a.strikeHalfLife) {
+			return ka.Addr
+		}
+	}
+	return nil
+}
+
+// pickRandom picks a random address from a random old or new bucket, which
+// must not all be empty.
+func (a *addrBook) pickRandom(fromOldBucket bool) *knownAddress {
+	var bucket map[string]*knownAddress
 	// loop until we pick a random non-empty bucket
 	for len(bucket) == 0 {
-		if pickFromOldBucket {
+		if fromOldBucket {
 			bucket = a.bucketsOld[a.rand.Intn(len(a.bucketsOld))]
 		} else {
 			bucket = a.bucketsNew[a.rand.Intn(len(a.bucketsNew))]
@@ -279,7 +301,7 @@ func (a *addrBook) PickAddress(biasTowardsNewAddrs int) *p2p.NetAddress {
 	randIndex := a.rand.Intn(len(bucket))
 	for _, ka := range bucket {
 		if randIndex == 0 {
-			return ka.Addr
+			return ka
 		}
 		randIndex--
 	}
@@ -312,10 +334,26 @@ func (a *addrBook) MarkAttempt(addr *p2p.NetAddress) {
 	ka.markAttempt()
 }
 
-// MarkBad implements AddrBook. Currently it just ejects the address.
-// TODO: black list for some amount of time
-func (a *addrBook) MarkBad(addr *p2p.NetAddress) {
-	a.RemoveAddress(addr)
+// MarkBad implements AddrBook - it gives the address severity strikes for
+// misbehaving. Addresses with strikes are picked less often, and not at all
+// once they have badStrikes. Strikes halve every strike half-life, so the
+// address recovers eventually.
+func (a *addrBook) MarkBad(addr *p2p.NetAddress, severity float64) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	ka := a.addrLookup[addr.ID]
+	if ka == nil {
+		return
+	}
+	ka.addStrike(severity, a.now(), a.strikeHalfLife)
+}
+
+// SetStrikeHalfLife sets the time it takes for the strikes given by MarkBad
+// to halve.
+func (a *addrBook) SetStrikeHalfLife(d time.Duration) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	a.strikeHalfLife = d
 }
 
 // GetSelection implements AddrBook.
@@ -340,12 +378,15 @@ func (a *addrBook) GetSelection() []*p2p.NetAddress {
 
 	// XXX: instead of making a list of all addresses, shuffling, and slicing a random chunk,
 	// could we just select a random numAddresses of indexes?
-	allAddr := make([]*p2p.NetAddress, bookSize)
-	i := 0
+	// Addresses which misbehaved are not shared.
+	now := a.now()
+	allAddr := make([]*p2p.NetAddress, 0, bookSize)
 	for _, ka := range a.addrLookup {
-		allAddr[i] = ka.Addr
-		i++
+		if ka.strikes(now, a.strikeHalfLife) < badStrikes {
+			allAddr = append(allAddr, ka.Addr)
+		}
 	}
+	numAddresses = cmn.MinInt(numAddresses, len(allAddr))
 
 	// Fisher-Yates shuffle the array. We only need to do the first
 	// `numAddresses' since we are throwing the rest.
@@ -504,7 +545,6 @@ out:
 		}
 	}
 	saveFileTicker.Stop()
-	a.saveToFile(a.filePath)
 }
 
 //----------------------------------------------------------
@@ -689,7 +729,7 @@ func (a *addrBook) addAddress(addr, src *p2p.NetAddress) error {
 func (a *addrBook) expireNew(bucketIdx int) {
 	for addrStr, ka := range a.bucketsNew[bucketIdx] {
 		// If an entry is bad, throw it away
-		if ka.isBad() {
+		if ka.isBad(a.now(), a.strikeHalfLife) {
 			a.Logger.Info(cmn.Fmt("expiring bad address %v", addrStr))
 			a.removeFromBucket(ka, bucketTypeNew, bucketIdx)
 			return
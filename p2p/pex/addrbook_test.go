@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -104,6 +105,27 @@ func TestAddrBookSaveLoad(t *testing.T) {
 	assert.Equal(t, 100, book.Size())
 }
 
+func TestAddrBookSavesOnStop(t *testing.T) {
+	// start without a file, Stop writes it
+	fname := createTempFileName("addrbook_test")
+	deleteTempFile(fname)
+	defer deleteTempFile(fname)
+
+	book := NewAddrBook(fname, true)
+	book.SetLogger(log.TestingLogger())
+	require.Nil(t, book.Start())
+	for _, addrSrc := range randNetAddressPairs(t, 10) {
+		book.AddAddress(addrSrc.addr, addrSrc.src)
+	}
+	require.Nil(t, book.Stop())
+
+	// the book is on disk as soon as Stop returns
+	book = NewAddrBook(fname, true)
+	book.SetLogger(log.TestingLogger())
+	book.loadFromFile(fname)
+	assert.Equal(t, 10, book.Size())
+}
+
 func TestAddrBookLookup(t *testing.T) {
 	fname := createTempFileName("addrbook_test")
 	defer deleteTempFile(fname)
@@ -417,3 +439,89 @@ func TestPrivatePeers(t *testing.T) {
 		assert.True(t, ok)
 	}
 }
+
+func TestAddrBookMarkBadStrikesDecay(t *testing.T) {
+	fname := createTempFileName("addrbook_test")
+	defer deleteTempFile(fname)
+
+	book := NewAddrBook(fname, true)
+	book.SetLogger(log.TestingLogger())
+	now := time.Now()
+	book.now = func() time.Time { return now }
+
+	addr := randIPv4Address(t)
+	book.AddAddress(addr, addr)
+	ka := book.addrLookup[addr.ID]
+
+	book.MarkBad(addr, 4)
+	assert.True(t, ka.isBad(now, defaultStrikeHalfLife))
+	assert.Zero(t, ka.pickWeight(now, defaultStrikeHalfLife))
+
+	// strikes halve every half-life
+	now = now.Add(defaultStrikeHalfLife)
+	assert.InDelta(t, 2, ka.strikes(now, defaultStrikeHalfLife), 1e-9)
+	assert.InDelta(t, 1.0/3, ka.pickWeight(now, defaultStrikeHalfLife), 1e-9)
+
+	// new strikes add up to the decayed ones
+	book.MarkBad(addr, 1)
+	assert.InDelta(t, 3, ka.strikes(now, defaultStrikeHalfLife), 1e-9)
+
+	// and the address recovers eventually
+	now = now.Add(10 * defaultStrikeHalfLife)
+	assert.InDelta(t, 1, ka.pickWeight(now, defaultStrikeHalfLife), 0.01)
+
+	// unknown addresses are ignored
+	book.MarkBad(randIPv4Address(t), 10)
+	assert.Equal(t, 1, book.Size())
+}
+
+func TestAddrBookPickAddressAvoidsStruckAddresses(t *testing.T) {
+	fname := createTempFileName("addrbook_test")
+	defer deleteTempFile(fname)
+
+	book := NewAddrBook(fname, true)
+	book.SetLogger(log.TestingLogger())
+
+	good, struck := randIPv4Address(t), randIPv4Address(t)
+	book.AddAddress(good, good)
+	book.AddAddress(struck, struck)
+
+	// 1 strike halves the chance to be picked
+	book.MarkBad(struck, 1)
+	picks := map[p2p.ID]int{}
+	for i := 0; i < 1000; i++ {
+		if addr := book.PickAddress(100); addr != nil {
+			picks[addr.ID]++
+		}
+	}
+	ratio := float64(picks[struck.ID]) / float64(picks[good.ID]+picks[struck.ID])
+	assert.True(t, ratio > 0.2 && ratio < 0.45, "struck address picked %.2f of the time", ratio)
+
+	// bad addresses are never picked nor shared
+	book.MarkBad(struck, badStrikes)
+	for i := 0; i < 100; i++ {
+		assert.NotEqual(t, struck, book.PickAddress(100))
+	}
+	assert.Equal(t, []*p2p.NetAddress{good}, book.GetSelection())
+}
+
+func TestAddrBookStrikesSaveLoad(t *testing.T) {
+	fname := createTempFileName("addrbook_test")
+	defer deleteTempFile(fname)
+
+	book := NewAddrBook(fname, true)
+	book.SetLogger(log.TestingLogger())
+
+	addr := randIPv4Address(t)
+	book.AddAddress(addr, addr)
+	book.MarkBad(addr, 2*badStrikes)
+	book.saveToFile(fname)
+
+	book = NewAddrBook(fname, true)
+	book.SetLogger(log.TestingLogger())
+	book.loadFromFile(fname)
+
+	require.Equal(t, 1, book.Size())
+	assert.True(t, book.addrLookup[addr.ID].isBad(time.Now(), defaultStrikeHalfLife))
+	assert.Empty(t, book.GetSelection())
+}
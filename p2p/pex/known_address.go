@@ -1,6 +1,7 @@
 package pex
 
 import (
+	"math"
 	"time"
 
 	"github.com/tendermint/tendermint/p2p"
@@ -16,6 +17,8 @@ type knownAddress struct {
 	LastSuccess time.Time       `json:"last_success"`
 	BucketType  byte            `json:"bucket_type"`
 	Buckets     []int           `json:"buckets"`
+	Strikes     float64         `json:"strikes"` // penalty for misbehaving, as of LastStrike
+	LastStrike  time.Time       `json:"last_strike"`
 }
 
 func newKnownAddress(addr *p2p.NetAddress, src *p2p.NetAddress) *knownAddress {
@@ -42,6 +45,8 @@ func (ka *knownAddress) copy() *knownAddress {
 		LastSuccess: ka.LastSuccess,
 		BucketType:  ka.BucketType,
 		Buckets:     ka.Buckets,
+		Strikes:     ka.Strikes,
+		LastStrike:  ka.LastStrike,
 	}
 }
 
@@ -66,6 +71,31 @@ func (ka *knownAddress) markGood() {
 	ka.LastSuccess = now
 }
 
+// addStrike adds severity to the strikes decayed up to now.
+func (ka *knownAddress) addStrike(severity float64, now time.Time, halfLife time.Duration) {
+	ka.Strikes = ka.strikes(now, halfLife) + severity
+	ka.LastStrike = now
+}
+
+// strikes returns the strikes decayed up to now. They halve every halfLife.
+func (ka *knownAddress) strikes(now time.Time, halfLife time.Duration) float64 {
+	if ka.Strikes == 0 || !now.After(ka.LastStrike) {
+		return ka.Strikes
+	}
+	elapsed := now.Sub(ka.LastStrike)
+	return ka.Strikes * math.Pow(0.5, float64(elapsed)/float64(halfLife))
+}
+
+// pickWeight returns the relative chance of the address being picked, given
+// its strikes. Addresses with badStrikes or more are never picked.
+func (ka *knownAddress) pickWeight(now time.Time, halfLife time.Duration) float64 {
+	strikes := ka.strikes(now, halfLife)
+	if strikes >= badStrikes {
+		return 0
+	}
+	return 1 / (1 + strikes)
+}
+
 func (ka *knownAddress) addBucketRef(bucketIdx int) int {
 	for _, bucket := range ka.Buckets {
 		if bucket == bucketIdx {
@@ -95,7 +125,8 @@ func (ka *knownAddress) removeBucketRef(bucketIdx int) int {
 }
 
 /*
-   An address is bad if the address in question is a New address, has not been tried in the last
+   An address is bad if it has collected at least badStrikes (decayed) strikes for
+   misbehaving, or if the address in question is a New address, has not been tried in the last
    minute, and meets one of the following criteria:
 
    1) It claims to be from the future
@@ -107,14 +138,19 @@ func (ka *knownAddress) removeBucketRef(bucketIdx int) int {
    worth keeping hold of.
 
 */
-func (ka *knownAddress) isBad() bool {
+func (ka *knownAddress) isBad(now time.Time, strikeHalfLife time.Duration) bool {
+	// Misbehaved --> bad
+	if ka.strikes(now, strikeHalfLife) >= badStrikes {
+		return true
+	}
+
 	// Is Old --> good
 	if ka.BucketType == bucketTypeOld {
 		return false
 	}
 
 	// Has been attempted in the last minute --> good
-	if ka.LastAttempt.After(time.Now().Add(-1 * time.Minute)) {
+	if ka.LastAttempt.After(now.Add(-1 * time.Minute)) {
 		return false
 	}
 
@@ -122,7 +158,7 @@ func (ka *knownAddress) isBad() bool {
 
 	// Too old?
 	// TODO: should be a timestamp of last seen, not just last attempt
-	if ka.LastAttempt.Before(time.Now().Add(-1 * numMissingDays * time.Hour * 24)) {
+	if ka.LastAttempt.Before(now.Add(-1 * numMissingDays * time.Hour * 24)) {
 		return true
 	}
 
@@ -132,7 +168,7 @@ func (ka *knownAddress) isBad() bool {
 	}
 
 	// Hasn't succeeded in too long?
-	if ka.LastSuccess.Before(time.Now().Add(-1*minBadDays*time.Hour*24)) &&
+	if ka.LastSuccess.Before(now.Add(-1*minBadDays*time.Hour*24)) &&
 		ka.Attempts >= maxFailures {
 		return true
 	}
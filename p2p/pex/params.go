@@ -43,6 +43,15 @@ const (
 	// days since the last success before we will consider evicting an address.
 	minBadDays = 7
 
+	// strikes (see MarkBad) at which an address is considered bad.
+	badStrikes = 3.0
+
+	// time it takes for strikes to halve by default.
+	defaultStrikeHalfLife = time.Hour
+
+	// picks PickAddress makes before giving up on penalized addresses.
+	maxPickAttempts = 10
+
 	// % of total addresses known returned by GetSelection.
 	getSelectionPercent = 23
 
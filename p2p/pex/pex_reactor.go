@@ -443,7 +443,7 @@ func (r *PEXReactor) dialPeer(addr *p2p.NetAddress) {
 
 	if attempts > maxAttemptsToDial {
 		r.Logger.Error("Reached max attempts to dial", "addr", addr, "attempts", attempts)
-		r.book.MarkBad(addr)
+		r.book.RemoveAddress(addr)
 		return
 	}
 
@@ -463,7 +463,7 @@ func (r *PEXReactor) dialPeer(addr *p2p.NetAddress) {
 		r.Logger.Error("Dialing failed", "addr", addr, "err", err, "attempts", attempts)
 		// TODO: detect more "bad peer" scenarios
 		if _, ok := err.(p2p.ErrSwitchAuthenticationFailure); ok {
-			r.book.MarkBad(addr)
+			r.book.MarkBad(addr, badStrikes)
 			r.attemptsToDial.Delete(addr.DialString())
 		} else {
 			r.book.MarkAttempt(addr)
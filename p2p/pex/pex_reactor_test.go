@@ -405,6 +405,7 @@ func (mockPeer) TrySend(byte, []byte) bool     { return false }
 func (mockPeer) Set(string, interface{})       {}
 func (mockPeer) Get(string) interface{}        { return nil }
 func (mockPeer) OriginalAddr() *p2p.NetAddress { return nil }
+func (mockPeer) FlushStop(time.Duration)       {}
 
 func assertPeersWithTimeout(
 	t *testing.T,
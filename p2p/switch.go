@@ -2,8 +2,10 @@ package p2p
 
 import (
 	"fmt"
+	"io"
 	"math"
 	"net"
+	"strings"
 	"sync"
 	"time"
 
@@ -30,6 +32,12 @@ const (
 	// keep at least this many outbound peers
 	// TODO: move to config
 	DefaultMinNumOutboundPeers = 10
+
+	// strikes (see AddrBook.MarkBad) for a peer stopped due to an error
+	errorStrikes = 1
+	// strikes for a peer on another network, enough to stop dialing it for
+	// a while
+	networkMismatchStrikes = 10
 )
 
 //-----------------------------------------------------------------------------
@@ -41,6 +49,7 @@ type AddrBook interface {
 	AddOurAddress(*NetAddress)
 	OurAddress(*NetAddress) bool
 	MarkGood(*NetAddress)
+	MarkBad(*NetAddress, float64)
 	RemoveAddress(*NetAddress)
 	HasAddress(*NetAddress) bool
 	Save()
@@ -214,11 +223,18 @@ func (sw *Switch) OnStop() {
 		listener.Stop()
 	}
 	sw.listeners = nil
-	// Stop peers
+	// Stop peers, letting each send what's already queued for it. Listeners
+	// are closed first so no new peers show up meanwhile.
+	var wg sync.WaitGroup
 	for _, peer := range sw.peers.List() {
-		peer.Stop()
 		sw.peers.Remove(peer)
+		wg.Add(1)
+		go func(peer Peer) {
+			defer wg.Done()
+			peer.FlushStop(sw.config.StopTimeout)
+		}(peer)
 	}
+	wg.Wait()
 	// Stop reactors
 	sw.Logger.Debug("Switch: Stopping reactors")
 	for _, reactor := range sw.reactors {
@@ -274,12 +290,19 @@ func (sw *Switch) Peers() IPeerSet {
 }
 
 // StopPeerForError disconnects from a peer due to external error.
+// If we dialed the peer, its address is penalized in the address book,
+// unless the connection was just closed (e.g. the peer shut down).
 // If the peer is persistent, it will attempt to reconnect.
-// TODO: make record depending on reason.
 func (sw *Switch) StopPeerForError(peer Peer, reason interface{}) {
 	sw.Logger.Error("Stopping peer for error", "peer", peer, "err", reason)
 	sw.stopAndRemovePeer(peer, reason)
 
+	// NOTE: inbound peers are skipped, all we know is their self-reported
+	// listen address.
+	if addr := peer.OriginalAddr(); addr != nil && sw.addrBook != nil && !isConnClosed(reason) {
+		sw.addrBook.MarkBad(addr, errorStrikes)
+	}
+
 	if peer.IsPersistent() {
 		addr := peer.OriginalAddr()
 		if addr == nil {
@@ -291,6 +314,21 @@ func (sw *Switch) StopPeerForError(peer Peer, reason interface{}) {
 	}
 }
 
+// isConnClosed returns true if reason is the connection being closed, which
+// is how a peer shutting down looks, rather than the peer misbehaving.
+func isConnClosed(reason interface{}) bool {
+	err, ok := reason.(error)
+	if !ok {
+		return false
+	}
+	switch err {
+	case io.EOF, io.ErrUnexpectedEOF, io.ErrClosedPipe:
+		return true
+	}
+	// net doesn't export this error
+	return strings.Contains(err.Error(), "use of closed network connection")
+}
+
 // StopPeerGracefully disconnects from a peer gracefully.
 // TODO: handle graceful disconnects.
 func (sw *Switch) StopPeerGracefully(peer Peer) {
@@ -626,7 +664,13 @@ func (sw *Switch) addPeer(pc peerConn) error {
 
 	// Check version, chain id
 	if err := sw.nodeInfo.CompatibleWith(peerNodeInfo); err != nil {
-		return err
+		if _, ok := err.(ErrSwitchNetworkMismatch); !ok || !sw.config.AllowNetworkMismatch {
+			if ok && sw.addrBook != nil {
+				// don't bother dialing it again
+				sw.addrBook.MarkBad(peerNodeInfo.NetAddress(), networkMismatchStrikes)
+			}
+			return err
+		}
 	}
 
 	peer := newPeer(pc, sw.mConfig, peerNodeInfo, sw.reactorsByCh, sw.chDescs, sw.StopPeerForError)
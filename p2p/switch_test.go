@@ -3,6 +3,7 @@ package p2p
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"net"
 	"sync"
 	"testing"
@@ -68,6 +69,8 @@ func (tr *TestReactor) Receive(chID byte, peer Peer, msgBytes []byte) {
 		tr.mtx.Lock()
 		defer tr.mtx.Unlock()
 		//fmt.Printf("Received: %X, %X\n", chID, msgBytes)
+		// msgBytes is reused by the conn
+		msgBytes = append([]byte(nil), msgBytes...)
 		tr.msgsReceived[chID] = append(tr.msgsReceived[chID], PeerMessage{peer.ID(), msgBytes, tr.msgsCounter})
 		tr.msgsCounter++
 	}
@@ -92,7 +95,8 @@ func MakeSwitchPair(t testing.TB, initSwitch func(int, *Switch) *Switch) (*Switc
 func initSwitchFunc(i int, sw *Switch) *Switch {
 	sw.SetAddrBook(&addrBookMock{
 		addrs:    make(map[string]struct{}),
-		ourAddrs: make(map[string]struct{})})
+		ourAddrs: make(map[string]struct{}),
+		badAddrs: make(map[string]struct{})})
 
 	// Make two reactors of two channels each
 	sw.AddReactor("foo", NewTestReactor([]*conn.ChannelDescriptor{
@@ -151,6 +155,50 @@ func assertMsgReceivedWithTimeout(t *testing.T, msgBytes []byte, channel byte, r
 	}
 }
 
+func TestSwitchFlushesPeersOnStop(t *testing.T) {
+	// throttle sending, so messages are still queued when s1 stops
+	p2pCfg := *cfg
+	p2pCfg.SendRate = 5000
+	p2pCfg.StopTimeout = 10 * time.Second
+	initSwitch := func(i int, sw *Switch) *Switch {
+		sw.SetAddrBook(&addrBookMock{
+			addrs:    make(map[string]struct{}),
+			ourAddrs: make(map[string]struct{}),
+			badAddrs: make(map[string]struct{})})
+		sw.AddReactor("foo", NewTestReactor([]*conn.ChannelDescriptor{
+			{ID: byte(0x00), Priority: 10, SendQueueCapacity: 100},
+		}, true))
+		return sw
+	}
+	switches := MakeConnectedSwitches(&p2pCfg, 2, initSwitch, Connect2Switches)
+	s1, s2 := switches[0], switches[1]
+	defer s2.Stop()
+
+	const numMsgs = 10
+	peer := s1.Peers().List()[0]
+	for i := 0; i < numMsgs; i++ {
+		require.True(t, peer.Send(byte(0x00), append([]byte{byte(i)}, make([]byte, 499)...)))
+	}
+	reactor := s2.Reactor("foo").(*TestReactor)
+	require.True(t, len(reactor.getMsgs(byte(0x00))) < numMsgs, "s1 should still have queued messages")
+
+	require.Nil(t, s1.Stop())
+	assert.False(t, peer.IsRunning())
+
+	// everything queued before the stop makes it to s2, in order
+	var msgs []PeerMessage
+	for start := time.Now(); time.Since(start) < time.Second; time.Sleep(10 * time.Millisecond) {
+		if msgs = reactor.getMsgs(byte(0x00)); len(msgs) == numMsgs {
+			break
+		}
+	}
+	require.Len(t, msgs, numMsgs)
+	for i, msg := range msgs {
+		assert.EqualValues(t, i, msg.Bytes[0])
+	}
+	assertNoPeersAfterTimeout(t, s2, 100*time.Millisecond)
+}
+
 func TestConnAddrFilter(t *testing.T) {
 	s1 := MakeSwitch(cfg, 1, "testing", "123.123.123", initSwitchFunc)
 	s2 := MakeSwitch(cfg, 1, "testing", "123.123.123", initSwitchFunc)
@@ -206,6 +254,61 @@ func TestSwitchFiltersOutItself(t *testing.T) {
 	assertNoPeersAfterTimeout(t, s1, 100*time.Millisecond)
 }
 
+func TestSwitchRejectsPeerOnOtherNetwork(t *testing.T) {
+	s1 := MakeSwitch(cfg, 1, "testing", "123.123.123", initSwitchFunc)
+	s2 := MakeSwitch(cfg, 1, "other", "123.123.123", initSwitchFunc)
+	defer s1.Stop()
+	defer s2.Stop()
+
+	c1, c2 := conn.NetPipe()
+
+	errCh1, errCh2 := make(chan error, 1), make(chan error, 1)
+	go func() { errCh1 <- s1.addPeerWithConnection(c1) }()
+	go func() { errCh2 <- s2.addPeerWithConnection(c2) }()
+
+	// whoever notices first drops the connection, so the other side may
+	// fail the handshake instead
+	mismatches := 0
+	for _, tc := range []struct {
+		errCh <-chan error
+		sw    *Switch
+		other *Switch
+	}{{errCh1, s1, s2}, {errCh2, s2, s1}} {
+		err := <-tc.errCh
+		require.Error(t, err)
+		if _, ok := err.(ErrSwitchNetworkMismatch); ok {
+			mismatches++
+			book := tc.sw.addrBook.(*addrBookMock)
+			assert.Contains(t, book.badAddrs, tc.other.NodeInfo().NetAddress().String())
+		}
+	}
+	assert.NotZero(t, mismatches)
+
+	assertNoPeersAfterTimeout(t, s1, 100*time.Millisecond)
+	assertNoPeersAfterTimeout(t, s2, 100*time.Millisecond)
+}
+
+func TestSwitchAllowsNetworkMismatch(t *testing.T) {
+	c := *cfg
+	c.AllowNetworkMismatch = true
+	s1 := MakeSwitch(&c, 1, "testing", "123.123.123", initSwitchFunc)
+	s2 := MakeSwitch(&c, 1, "other", "123.123.123", initSwitchFunc)
+	require.NoError(t, StartSwitches([]*Switch{s1, s2}))
+	defer s1.Stop()
+	defer s2.Stop()
+
+	c1, c2 := conn.NetPipe()
+
+	errCh := make(chan error, 2)
+	go func() { errCh <- s1.addPeerWithConnection(c1) }()
+	go func() { errCh <- s2.addPeerWithConnection(c2) }()
+
+	assert.NoError(t, <-errCh)
+	assert.NoError(t, <-errCh)
+	assert.Equal(t, 1, s1.Peers().Size())
+	assert.Equal(t, 1, s2.Peers().Size())
+}
+
 func assertNoPeersAfterTimeout(t *testing.T, sw *Switch, timeout time.Duration) {
 	time.Sleep(timeout)
 	if sw.Peers().Size() != 0 {
@@ -276,6 +379,52 @@ func TestSwitchStopsNonPersistentPeerOnError(t *testing.T) {
 
 	assertNoPeersAfterTimeout(t, sw, 100*time.Millisecond)
 	assert.False(peer.IsRunning())
+
+	// a closed connection isn't the peer's fault
+	assert.Empty(sw.addrBook.(*addrBookMock).badAddrs)
+}
+
+func TestSwitchMarksPeerBadOnError(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+
+	sw := MakeSwitch(cfg, 1, "testing", "123.123.123", initSwitchFunc)
+	err := sw.Start()
+	require.Nil(err)
+	defer sw.Stop()
+
+	rp := &remotePeer{PrivKey: ed25519.GenPrivKey(), Config: cfg}
+	rp.Start()
+	defer rp.Stop()
+
+	pc, err := newOutboundPeerConn(rp.Addr(), cfg, false, sw.nodeKey.PrivKey)
+	require.Nil(err)
+	err = sw.addPeer(pc)
+	require.Nil(err)
+
+	peer := sw.Peers().Get(rp.ID())
+	require.NotNil(peer)
+	sw.StopPeerForError(peer, fmt.Errorf("Unknown channel %X", 0xff))
+
+	assert.Zero(sw.Peers().Size())
+	assert.Contains(sw.addrBook.(*addrBookMock).badAddrs, rp.Addr().String())
+}
+
+func TestIsConnClosed(t *testing.T) {
+	testCases := []struct {
+		reason interface{}
+		closed bool
+	}{
+		{io.EOF, true},
+		{io.ErrUnexpectedEOF, true},
+		{io.ErrClosedPipe, true},
+		{&net.OpError{Op: "read", Net: "tcp", Err: fmt.Errorf("use of closed network connection")}, true},
+		{fmt.Errorf("pong timeout"), false},
+		{"some panic", false},
+		{nil, false},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.closed, isConnClosed(tc.reason), "%v", tc.reason)
+	}
 }
 
 func TestSwitchReconnectsToPersistentPeer(t *testing.T) {
@@ -404,6 +553,7 @@ func BenchmarkSwitchBroadcast(b *testing.B) {
 type addrBookMock struct {
 	addrs    map[string]struct{}
 	ourAddrs map[string]struct{}
+	badAddrs map[string]struct{}
 }
 
 var _ AddrBook = (*addrBookMock)(nil)
@@ -418,6 +568,9 @@ func (book *addrBookMock) OurAddress(addr *NetAddress) bool {
 	return ok
 }
 func (book *addrBookMock) MarkGood(*NetAddress) {}
+func (book *addrBookMock) MarkBad(addr *NetAddress, severity float64) {
+	book.badAddrs[addr.String()] = struct{}{}
+}
 func (book *addrBookMock) HasAddress(addr *NetAddress) bool {
 	_, ok := book.addrs[addr.String()]
 	return ok
@@ -12,6 +12,7 @@ import (
 	"github.com/tendermint/tendermint/crypto/ed25519"
 	cmn "github.com/tendermint/tendermint/libs/common"
 	"github.com/tendermint/tendermint/types"
+	tmtime "github.com/tendermint/tendermint/types/time"
 )
 
 // TODO: type ?
@@ -78,6 +79,21 @@ func GenFilePV(filePath string) *FilePV {
 	}
 }
 
+// GenFilePVFromSecret generates a new validator whose private key is derived
+// from the secret with ed25519.GenPrivKeyFromSecret and sets the filePath, but
+// does not call Save(). The same secret always yields the same key, so this is
+// only meant for reproducible test networks.
+func GenFilePVFromSecret(filePath string, secret []byte) *FilePV {
+	privKey := ed25519.GenPrivKeyFromSecret(secret)
+	return &FilePV{
+		Address:  privKey.PubKey().Address(),
+		PubKey:   privKey.PubKey(),
+		PrivKey:  privKey,
+		LastStep: stepNone,
+		filePath: filePath,
+	}
+}
+
 // LoadFilePV loads a FilePV from the filePath.  The FilePV handles double
 // signing prevention by persisting data to the filePath.  If the filePath does
 // not exist, the FilePV must be created manually and saved.
@@ -324,7 +340,7 @@ func checkVotesOnlyDifferByTimestamp(lastSignBytes, newSignBytes []byte) (time.T
 	}
 
 	// set the times to the same value and check equality
-	now := types.CanonicalTime(time.Now())
+	now := types.CanonicalTime(tmtime.Now())
 	lastVote.Timestamp = now
 	newVote.Timestamp = now
 	lastVoteBytes, _ := cdc.MarshalJSON(lastVote)
@@ -350,7 +366,7 @@ func checkProposalsOnlyDifferByTimestamp(lastSignBytes, newSignBytes []byte) (ti
 	}
 
 	// set the times to the same value and check equality
-	now := types.CanonicalTime(time.Now())
+	now := types.CanonicalTime(tmtime.Now())
 	lastProposal.Timestamp = now
 	newProposal.Timestamp = now
 	lastProposalBytes, _ := cdc.MarshalJSON(lastProposal)
@@ -46,6 +46,20 @@ func TestLoadOrGenValidator(t *testing.T) {
 	assert.Equal(addr, privVal.GetAddress(), "expected privval addr to be the same")
 }
 
+func TestGenFilePVFromSecret(t *testing.T) {
+	assert := assert.New(t)
+
+	pv1 := GenFilePVFromSecret("", []byte("mintnet:0"))
+	pv2 := GenFilePVFromSecret("", []byte("mintnet:0"))
+	pv3 := GenFilePVFromSecret("", []byte("mintnet:1"))
+
+	assert.Equal(pv1.PrivKey, pv2.PrivKey, "same secret must give the same key")
+	assert.NotEqual(pv1.PrivKey, pv3.PrivKey, "different secrets must give different keys")
+
+	// golden value: the derivation must never silently change
+	assert.Equal("E7C5058A9619DBE99E647617AB34C9A5E10206D6", fmt.Sprintf("%X", pv1.GetAddress()))
+}
+
 func TestUnmarshalValidator(t *testing.T) {
 	assert, require := assert.New(t), require.New(t)
 
@@ -102,6 +102,14 @@ func (c Client) BroadcastTxSync(tx types.Tx) (*ctypes.ResultBroadcastTx, error)
 	return core.BroadcastTxSync(tx)
 }
 
+func (c Client) UnconfirmedTxs(limit int) (*ctypes.ResultUnconfirmedTxs, error) {
+	return core.UnconfirmedTxs(limit)
+}
+
+func (c Client) NumUnconfirmedTxs() (*ctypes.ResultUnconfirmedTxs, error) {
+	return core.NumUnconfirmedTxs()
+}
+
 func (c Client) NetInfo() (*ctypes.ResultNetInfo, error) {
 	return core.NetInfo()
 }
@@ -126,6 +134,10 @@ func (c Client) Block(height *int64) (*ctypes.ResultBlock, error) {
 	return core.Block(height)
 }
 
+func (c Client) BlockByHash(hash []byte) (*ctypes.ResultBlock, error) {
+	return core.BlockByHash(hash)
+}
+
 func (c Client) Commit(height *int64) (*ctypes.ResultCommit, error) {
 	return core.Commit(height)
 }
@@ -11,6 +11,7 @@ import (
 	abci "github.com/tendermint/tendermint/abci/types"
 
 	"github.com/tendermint/tendermint/rpc/client"
+	rpccore "github.com/tendermint/tendermint/rpc/core"
 	rpctest "github.com/tendermint/tendermint/rpc/test"
 	"github.com/tendermint/tendermint/types"
 )
@@ -36,9 +37,14 @@ func GetClients() []client.Client {
 func TestStatus(t *testing.T) {
 	for i, c := range GetClients() {
 		moniker := rpctest.GetConfig().Moniker
+		require.Nil(t, client.WaitForHeight(c, 1, nil))
 		status, err := c.Status()
 		require.Nil(t, err, "%d: %+v", i, err)
 		assert.Equal(t, moniker, status.NodeInfo.Moniker)
+		assert.True(t, status.SyncInfo.LatestBlockHeight > 0, "%d", i)
+		assert.NotEmpty(t, status.SyncInfo.LatestBlockHash, "%d", i)
+		assert.False(t, status.SyncInfo.LatestBlockTime.IsZero(), "%d", i)
+		assert.False(t, status.SyncInfo.CatchingUp, "%d", i)
 	}
 }
 
@@ -186,6 +192,13 @@ func TestAppCalls(t *testing.T) {
 		assert.True(len(appHash) > 0)
 		assert.EqualValues(apph, block.BlockMeta.Header.Height)
 
+		// and look it up by hash
+		byHash, err := c.BlockByHash(block.BlockMeta.BlockID.Hash)
+		require.Nil(err, "%d: %+v", i, err)
+		assert.EqualValues(block.BlockMeta, byHash.BlockMeta)
+		_, err = c.BlockByHash([]byte("bogus"))
+		assert.NotNil(err, "%d", i)
+
 		// now check the results
 		blockResults, err := c.BlockResults(&txh)
 		require.Nil(err, "%d: %+v", i, err)
@@ -248,6 +261,20 @@ func TestBroadcastTxSync(t *testing.T) {
 	}
 }
 
+func TestUnconfirmedTxs(t *testing.T) {
+	// blocks are committed too quickly to count on a tx staying in the
+	// mempool; rpc/core tests the numbers, this just checks the plumbing
+	for i, c := range GetClients() {
+		res, err := c.UnconfirmedTxs(1)
+		require.Nil(t, err, "%d: %+v", i, err)
+		assert.True(t, res.N <= 1, "%d", i)
+
+		num, err := c.NumUnconfirmedTxs()
+		require.Nil(t, err, "%d: %+v", i, err)
+		assert.Empty(t, num.Txs, "%d", i)
+	}
+}
+
 func TestBroadcastTxCommit(t *testing.T) {
 	require := require.New(t)
 
@@ -259,10 +286,41 @@ func TestBroadcastTxCommit(t *testing.T) {
 		require.True(bres.CheckTx.IsOK())
 		require.True(bres.DeliverTx.IsOK())
 
+		require.True(bres.Committed)
+
 		require.Equal(0, mempool.Size())
 	}
 }
 
+func TestBroadcastTxCommitTimeout(t *testing.T) {
+	// NOTE: GetClients configures rpccore, so override the config afterwards
+	clients := GetClients()
+
+	// make every broadcast_tx_commit give up before the next block
+	rpcConfig := *rpctest.GetConfig().RPC
+	rpcConfig.TimeoutBroadcastTxCommit = 1
+	rpccore.SetConfig(rpcConfig)
+	defer rpccore.SetConfig(*rpctest.GetConfig().RPC)
+
+	for i, c := range clients {
+		_, _, tx := MakeTxKV()
+		bres, err := c.BroadcastTxCommit(tx)
+		require.Nil(t, err, "%d: %+v", i, err)
+		assert.True(t, bres.CheckTx.IsOK(), "%d", i)
+		assert.False(t, bres.Committed, "%d", i)
+		assert.EqualValues(t, types.Tx(tx).Hash(), bres.Hash, "%d", i)
+
+		// the tx is still in the mempool and makes it into a later block
+		status, err := c.Status()
+		require.Nil(t, err, "%d: %+v", i, err)
+		err = client.WaitForHeight(c, status.SyncInfo.LatestBlockHeight+2, nil)
+		require.Nil(t, err, "%d: %+v", i, err)
+		ptx, err := c.Tx(bres.Hash, false)
+		require.Nil(t, err, "%d: %+v", i, err)
+		assert.EqualValues(t, tx, ptx.Tx, "%d", i)
+	}
+}
+
 func TestTx(t *testing.T) {
 	// first we broadcast a tx
 	c := getHTTPClient()
@@ -72,6 +72,8 @@ func BlockchainInfo(minHeight, maxHeight int64) (*ctypes.ResultBlockchainInfo, e
 	if err != nil {
 		return nil, err
 	}
+	// skip blocks that have been pruned
+	minHeight = cmn.MaxInt64(minHeight, blockStore.Base())
 	logger.Debug("BlockchainInfoHandler", "maxHeight", maxHeight, "minHeight", minHeight)
 
 	blockMetas := []*types.BlockMeta{}
@@ -220,12 +222,40 @@ func Block(heightPtr *int64) (*ctypes.ResultBlock, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := checkNotPruned(height); err != nil {
+		return nil, err
+	}
 
 	blockMeta := blockStore.LoadBlockMeta(height)
 	block := blockStore.LoadBlock(height)
 	return &ctypes.ResultBlock{blockMeta, block}, nil
 }
 
+// Get block by hash.
+//
+// ```shell
+// curl 'localhost:26657/block_by_hash?hash=0xD70952032620CC4E2737EB8AC379806359D8E0B17B0488F627997A0B043ABDED'
+// ```
+//
+// ```go
+// client := client.NewHTTP("tcp://0.0.0.0:26657", "/websocket")
+// info, err := client.BlockByHash(hash)
+// ```
+//
+// The result has the same structure as /block. An error is returned if no
+// block with that hash is stored. Blocks saved by versions without the hash
+// index are found once the node has indexed them in the background after
+// starting.
+func BlockByHash(hash []byte) (*ctypes.ResultBlock, error) {
+	block := blockStore.LoadBlockByHash(hash)
+	if block == nil {
+		return nil, fmt.Errorf("Block with hash %X not found", hash)
+	}
+
+	blockMeta := blockStore.LoadBlockMeta(block.Height)
+	return &ctypes.ResultBlock{BlockMeta: blockMeta, Block: block}, nil
+}
+
 // Get block commit at a given height.
 // If no height is provided, it will fetch the commit for the latest block.
 //
@@ -302,6 +332,9 @@ func Commit(heightPtr *int64) (*ctypes.ResultCommit, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := checkNotPruned(height); err != nil {
+		return nil, err
+	}
 
 	header := blockStore.LoadBlockMeta(height).Header
 
@@ -383,3 +416,12 @@ func getHeight(storeHeight int64, heightPtr *int64) (int64, error) {
 	}
 	return storeHeight, nil
 }
+
+// checkNotPruned returns an error if the block at the given height has been
+// pruned from the block store.
+func checkNotPruned(height int64) error {
+	if base := blockStore.Base(); height < base {
+		return sm.ErrBlockPruned{Height: height, Base: base}
+	}
+	return nil
+}
@@ -106,10 +106,13 @@ func BroadcastTxSync(tx types.Tx) (*ctypes.ResultBroadcastTx, error) {
 	}, nil
 }
 
-// CONTRACT: only returns error if mempool.BroadcastTx errs (ie. problem with the app)
-// or if we timeout waiting for tx to commit.
+// CONTRACT: only returns error if mempool.BroadcastTx errs (ie. problem with the app).
 // If CheckTx or DeliverTx fail, no error will be returned, but the returned result
 // will contain a non-OK ABCI code.
+// If the tx passes CheckTx but is not included in a block within
+// rpc.timeout_broadcast_tx_commit, the CheckTx result is returned with
+// committed set to false. The tx stays in the mempool and may still be committed
+// later; use /tx with the returned hash to find out.
 //
 // ```shell
 // curl 'localhost:26657/broadcast_tx_commit?tx="789"'
@@ -126,6 +129,7 @@ func BroadcastTxSync(tx types.Tx) (*ctypes.ResultBroadcastTx, error) {
 // {
 // 	"error": "",
 // 	"result": {
+// 		"committed": true,
 // 		"height": 26682,
 // 		"hash": "75CA0F856A4DA078FC4911580360E70CEFB2EBEE",
 // 		"deliver_tx": {
@@ -183,10 +187,9 @@ func BroadcastTxCommit(tx types.Tx) (*ctypes.ResultBroadcastTxCommit, error) {
 		}, nil
 	}
 
-	// Wait for the tx to be included in a block,
-	// timeout after something reasonable.
-	// TODO: configurable?
-	timer := time.NewTimer(60 * 2 * time.Second)
+	// Wait for the tx to be included in a block or timeout.
+	timer := time.NewTimer(config.TimeoutBroadcastTxCommit)
+	defer timer.Stop()
 	select {
 	case deliverTxResMsg := <-deliverTxResCh:
 		deliverTxRes := deliverTxResMsg.(types.EventDataTx)
@@ -198,14 +201,17 @@ func BroadcastTxCommit(tx types.Tx) (*ctypes.ResultBroadcastTxCommit, error) {
 			DeliverTx: deliverTxR,
 			Hash:      tx.Hash(),
 			Height:    deliverTxRes.Height,
+			Committed: true,
 		}, nil
 	case <-timer.C:
-		logger.Error("failed to include tx")
+		// The tx passed CheckTx and is still in the mempool, so report it as
+		// not yet committed instead of failing the request.
+		logger.Info("Timed out waiting for tx to be included in a block", "hash", cmn.HexBytes(tx.Hash()))
 		return &ctypes.ResultBroadcastTxCommit{
 			CheckTx:   *checkTxR,
 			DeliverTx: abci.ResponseDeliverTx{},
 			Hash:      tx.Hash(),
-		}, fmt.Errorf("Timed out waiting for transaction to be included in a block")
+		}, nil
 	}
 }
 
@@ -217,9 +223,12 @@ func BroadcastTxCommit(tx types.Tx) (*ctypes.ResultBroadcastTxCommit, error) {
 //
 // ```go
 // client := client.NewHTTP("tcp://0.0.0.0:26657", "/websocket")
-// result, err := client.UnconfirmedTxs()
+// result, err := client.UnconfirmedTxs(30)
 // ```
 //
+// `n_txs` is the number of txs returned; `total` and `total_bytes` describe
+// the whole mempool.
+//
 // > The above command returns JSON structured like this:
 //
 // ```json
@@ -227,7 +236,9 @@ func BroadcastTxCommit(tx types.Tx) (*ctypes.ResultBroadcastTxCommit, error) {
 //   "error": "",
 //   "result": {
 //     "txs": [],
-//     "n_txs": 0
+//     "n_txs": 0,
+//     "total": 0,
+//     "total_bytes": 0
 //   },
 //   "id": "",
 //   "jsonrpc": "2.0"
@@ -244,7 +255,11 @@ func UnconfirmedTxs(limit int) (*ctypes.ResultUnconfirmedTxs, error) {
 	limit = validatePerPage(limit)
 
 	txs := mempool.Reap(limit)
-	return &ctypes.ResultUnconfirmedTxs{len(txs), txs}, nil
+	return &ctypes.ResultUnconfirmedTxs{
+		N:          len(txs),
+		Total:      mempool.Size(),
+		TotalBytes: mempool.TxsBytes(),
+		Txs:        txs}, nil
 }
 
 // Get number of unconfirmed transactions.
@@ -255,7 +270,7 @@ func UnconfirmedTxs(limit int) (*ctypes.ResultUnconfirmedTxs, error) {
 //
 // ```go
 // client := client.NewHTTP("tcp://0.0.0.0:26657", "/websocket")
-// result, err := client.UnconfirmedTxs()
+// result, err := client.NumUnconfirmedTxs()
 // ```
 //
 // > The above command returns JSON structured like this:
@@ -265,12 +280,17 @@ func UnconfirmedTxs(limit int) (*ctypes.ResultUnconfirmedTxs, error) {
 //   "error": "",
 //   "result": {
 //     "txs": null,
-//     "n_txs": 0
+//     "n_txs": 0,
+//     "total": 0,
+//     "total_bytes": 0
 //   },
 //   "id": "",
 //   "jsonrpc": "2.0"
 // }
 // ```
 func NumUnconfirmedTxs() (*ctypes.ResultUnconfirmedTxs, error) {
-	return &ctypes.ResultUnconfirmedTxs{N: mempool.Size()}, nil
+	return &ctypes.ResultUnconfirmedTxs{
+		N:          mempool.Size(),
+		Total:      mempool.Size(),
+		TotalBytes: mempool.TxsBytes()}, nil
 }
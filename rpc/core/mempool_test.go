@@ -0,0 +1,48 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/abci/example/kvstore"
+	cfg "github.com/tendermint/tendermint/config"
+	mempl "github.com/tendermint/tendermint/mempool"
+	"github.com/tendermint/tendermint/proxy"
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestUnconfirmedTxs(t *testing.T) {
+	cc := proxy.NewLocalClientCreator(kvstore.NewKVStoreApplication())
+	appConn, err := cc.NewABCIClient()
+	require.NoError(t, err)
+	require.NoError(t, appConn.Start())
+	defer appConn.Stop()
+	mem := mempl.NewMempool(cfg.TestMempoolConfig(), appConn, 0)
+	SetMempool(mem)
+
+	res, err := NumUnconfirmedTxs()
+	require.NoError(t, err)
+	assert.Equal(t, 0, res.N)
+	assert.EqualValues(t, 0, res.TotalBytes)
+
+	txs := types.Txs{types.Tx("a=1"), types.Tx("bb=22"), types.Tx("ccc=333")}
+	for _, tx := range txs {
+		require.NoError(t, mem.CheckTx(tx, nil))
+	}
+
+	res, err = NumUnconfirmedTxs()
+	require.NoError(t, err)
+	assert.Equal(t, 3, res.N)
+	assert.Equal(t, 3, res.Total)
+	assert.EqualValues(t, 3+5+7, res.TotalBytes)
+	assert.Empty(t, res.Txs)
+
+	res, err = UnconfirmedTxs(2)
+	require.NoError(t, err)
+	assert.Equal(t, 2, res.N)
+	assert.Equal(t, 3, res.Total)
+	assert.EqualValues(t, 3+5+7, res.TotalBytes)
+	assert.Equal(t, []types.Tx(txs[:2]), res.Txs)
+}
@@ -3,6 +3,7 @@ package core
 import (
 	"time"
 
+	cfg "github.com/tendermint/tendermint/config"
 	"github.com/tendermint/tendermint/consensus"
 	crypto "github.com/tendermint/tendermint/crypto"
 	"github.com/tendermint/tendermint/p2p"
@@ -66,6 +67,8 @@ var (
 	eventBus         *types.EventBus // thread safe
 
 	logger log.Logger
+
+	config = *cfg.DefaultRPCConfig()
 )
 
 func SetStateDB(db dbm.DB) {
@@ -124,6 +127,11 @@ func SetEventBus(b *types.EventBus) {
 	eventBus = b
 }
 
+// SetConfig sets an RPCConfig.
+func SetConfig(c cfg.RPCConfig) {
+	config = c
+}
+
 func validatePage(page, perPage, totalCount int) int {
 	if perPage < 1 {
 		return 1
@@ -92,7 +92,10 @@ func Tx(hash []byte, prove bool) (*ctypes.ResultTx, error) {
 
 	var proof types.TxProof
 	if prove {
-		block := blockStore.LoadBlock(height)
+		block, err := blockStore.LoadBlockChecked(height)
+		if err != nil {
+			return nil, err
+		}
 		proof = block.Data.Txs.Proof(int(index)) // XXX: overflow on 32-bit machines
 	}
 
@@ -201,7 +204,10 @@ func TxSearch(query string, prove bool, page, perPage int) (*ctypes.ResultTxSear
 		index := r.Index
 
 		if prove {
-			block := blockStore.LoadBlock(height)
+			block, err := blockStore.LoadBlockChecked(height)
+			if err != nil {
+				return nil, err
+			}
 			proof = block.Data.Txs.Proof(int(index)) // XXX: overflow on 32-bit machines
 		}
 
@@ -154,12 +154,16 @@ type ResultBroadcastTx struct {
 	Hash cmn.HexBytes `json:"hash"`
 }
 
-// CheckTx and DeliverTx results
+// CheckTx and DeliverTx results.
+// Committed is false if the tx was rejected by CheckTx or was not included in
+// a block before the broadcast timeout; DeliverTx and Height are only set when
+// it is true.
 type ResultBroadcastTxCommit struct {
 	CheckTx   abci.ResponseCheckTx   `json:"check_tx"`
 	DeliverTx abci.ResponseDeliverTx `json:"deliver_tx"`
 	Hash      cmn.HexBytes           `json:"hash"`
 	Height    int64                  `json:"height"`
+	Committed bool                   `json:"committed"`
 }
 
 // Result of querying for a tx
@@ -180,8 +184,10 @@ type ResultTxSearch struct {
 
 // List of mempool txs
 type ResultUnconfirmedTxs struct {
-	N   int        `json:"n_txs"`
-	Txs []types.Tx `json:"txs"`
+	N          int        `json:"n_txs"`
+	Total      int        `json:"total"`
+	TotalBytes int64      `json:"total_bytes"`
+	Txs        []types.Tx `json:"txs"`
 }
 
 // Info abci msg
@@ -2,6 +2,7 @@ package core_grpc
 
 import (
 	"context"
+	"fmt"
 
 	abci "github.com/tendermint/tendermint/abci/types"
 	core "github.com/tendermint/tendermint/rpc/core"
@@ -20,6 +21,11 @@ func (bapi *broadcastAPI) BroadcastTx(ctx context.Context, req *RequestBroadcast
 	if err != nil {
 		return nil, err
 	}
+	// ResponseBroadcastTx has no way to say the tx is still pending, so keep
+	// reporting a timeout as an error rather than as an empty DeliverTx.
+	if res.CheckTx.IsOK() && !res.Committed {
+		return nil, fmt.Errorf("Timed out waiting for tx %X to be included in a block", res.Hash)
+	}
 	return &ResponseBroadcastTx{
 
 		CheckTx: &abci.ResponseCheckTx{
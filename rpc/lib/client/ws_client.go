@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"strconv"
 	"sync"
 	"time"
 
@@ -47,6 +48,10 @@ type WSClient struct {
 	// Callback, which will be called each time after successful reconnect.
 	onReconnect func()
 
+	// Callback, which will be called each time the connection is lost,
+	// before reconnecting.
+	onDisconnect func()
+
 	// internal channels
 	send            chan types.RPCRequest // user requests
 	backlog         chan types.RPCRequest // stores a single user request received during a conn failure
@@ -145,6 +150,15 @@ func OnReconnect(cb func()) func(*WSClient) {
 	}
 }
 
+// OnDisconnect sets the callback, which will be called every time the
+// connection is lost (e.g. the server stopped answering pings for readWait),
+// before reconnecting.
+func OnDisconnect(cb func()) func(*WSClient) {
+	return func(c *WSClient) {
+		c.onDisconnect = cb
+	}
+}
+
 // String returns WS client full address.
 func (c *WSClient) String() string {
 	return fmt.Sprintf("%s (%s)", c.Address, c.Endpoint)
@@ -267,6 +281,9 @@ func (c *WSClient) reconnect() error {
 	c.mtx.Lock()
 	c.reconnecting = true
 	c.mtx.Unlock()
+	if c.onDisconnect != nil {
+		go c.onDisconnect()
+	}
 	defer func() {
 		c.mtx.Lock()
 		c.reconnecting = false
@@ -400,13 +417,16 @@ func (c *WSClient) writeRoutine() {
 					c.Logger.Error("failed to set write deadline", "err", err)
 				}
 			}
-			if err := c.conn.WriteMessage(websocket.PingMessage, []byte{}); err != nil {
+			// the server echoes the payload back in its pong, so latency is
+			// measured against the right ping even if pongs arrive late
+			sentAt := time.Now()
+			if err := c.conn.WriteMessage(websocket.PingMessage, []byte(strconv.FormatInt(sentAt.UnixNano(), 10))); err != nil {
 				c.Logger.Error("failed to write ping", "err", err)
 				c.reconnectAfter <- err
 				return
 			}
 			c.mtx.Lock()
-			c.sentLastPingAt = time.Now()
+			c.sentLastPingAt = sentAt
 			c.mtx.Unlock()
 			c.Logger.Debug("sent ping")
 		case <-c.readRoutineQuit:
@@ -431,13 +451,25 @@ func (c *WSClient) readRoutine() {
 		c.wg.Done()
 	}()
 
-	c.conn.SetPongHandler(func(string) error {
+	c.conn.SetPongHandler(func(payload string) error {
 		// gather latency stats
-		c.mtx.RLock()
-		t := c.sentLastPingAt
-		c.mtx.RUnlock()
+		var t time.Time
+		if nanos, err := strconv.ParseInt(payload, 10, 64); err == nil {
+			t = time.Unix(0, nanos)
+		} else {
+			c.mtx.RLock()
+			t = c.sentLastPingAt
+			c.mtx.RUnlock()
+		}
 		c.PingPongLatencyTimer.UpdateSince(t)
 
+		// the server is alive, even if it has no data for us
+		if c.readWait > 0 {
+			if err := c.conn.SetReadDeadline(time.Now().Add(c.readWait)); err != nil {
+				c.Logger.Error("failed to set read deadline", "err", err)
+			}
+		}
+
 		c.Logger.Debug("got pong")
 		return nil
 	})
@@ -451,7 +483,8 @@ func (c *WSClient) readRoutine() {
 		}
 		_, data, err := c.conn.ReadMessage()
 		if err != nil {
-			if !websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure) {
+			// a read timeout means the server stopped answering our pings
+			if !websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure) && !isTimeout(err) {
 				return
 			}
 
@@ -478,6 +511,11 @@ func (c *WSClient) readRoutine() {
 	}
 }
 
+func isTimeout(err error) bool {
+	netErr, ok := err.(net.Error)
+	return ok && netErr.Timeout()
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 // Predefined methods
 
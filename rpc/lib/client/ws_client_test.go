@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/tendermint/tendermint/libs/log"
 
@@ -21,6 +22,7 @@ var wsCallTimeout = 5 * time.Second
 
 type myHandler struct {
 	closeConnAfterRead bool
+	ignorePings        bool
 	mtx                sync.RWMutex
 }
 
@@ -35,6 +37,9 @@ func (h *myHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		panic(err)
 	}
 	defer conn.Close() // nolint: errcheck
+	if h.ignorePings {
+		conn.SetPingHandler(func(string) error { return nil })
+	}
 	for {
 		messageType, _, err := conn.ReadMessage()
 		if err != nil {
@@ -120,6 +125,89 @@ func TestWSClientReconnectsAfterWriteFailure(t *testing.T) {
 	wg.Wait()
 }
 
+func TestWSClientReconnectsWhenPongsStop(t *testing.T) {
+	// start server
+	h := &myHandler{ignorePings: true}
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	disconnected := make(chan struct{}, 1)
+	reconnected := make(chan struct{}, 1)
+	c := NewWSClient(s.Listener.Addr().String(), "/websocket",
+		PingPeriod(50*time.Millisecond),
+		ReadWait(150*time.Millisecond),
+		OnDisconnect(func() {
+			select {
+			case disconnected <- struct{}{}:
+			default:
+			}
+		}),
+		OnReconnect(func() {
+			select {
+			case reconnected <- struct{}{}:
+			default:
+			}
+		}),
+	)
+	c.SetLogger(log.TestingLogger())
+	require.Nil(t, c.Start())
+	defer c.Stop()
+
+	// no pongs means the read deadline expires and the client redials
+	select {
+	case <-disconnected:
+	case <-time.After(5 * time.Second):
+		t.Fatal("client did not notice pongs stopped")
+	}
+	select {
+	case <-reconnected:
+	case <-time.After(5 * time.Second):
+		t.Fatal("client did not reconnect after pongs stopped")
+	}
+}
+
+func TestWSClientKeepsConnectionWhilePongsArrive(t *testing.T) {
+	// the server answers pings, but has no data for us
+	s := httptest.NewServer(&myHandler{})
+	defer s.Close()
+
+	disconnected := make(chan struct{}, 1)
+	c := NewWSClient(s.Listener.Addr().String(), "/websocket",
+		PingPeriod(50*time.Millisecond),
+		ReadWait(150*time.Millisecond),
+		OnDisconnect(func() {
+			select {
+			case disconnected <- struct{}{}:
+			default:
+			}
+		}),
+	)
+	c.SetLogger(log.TestingLogger())
+	require.Nil(t, c.Start())
+	defer c.Stop()
+
+	select {
+	case <-disconnected:
+		t.Fatal("client dropped a connection which answers pings")
+	case <-time.After(time.Second):
+	}
+	assert.False(t, c.IsReconnecting())
+}
+
+func TestWSClientPingPongLatency(t *testing.T) {
+	s := httptest.NewServer(&myHandler{})
+	defer s.Close()
+
+	c := NewWSClient(s.Listener.Addr().String(), "/websocket", PingPeriod(20*time.Millisecond))
+	c.SetLogger(log.TestingLogger())
+	require.Nil(t, c.Start())
+	defer c.Stop()
+
+	time.Sleep(200 * time.Millisecond)
+	require.True(t, c.PingPongLatencyTimer.Count() > 0)
+	require.True(t, c.PingPongLatencyTimer.Max() < int64(100*time.Millisecond))
+}
+
 func TestWSClientReconnectFailure(t *testing.T) {
 	// start server
 	h := &myHandler{}
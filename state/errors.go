@@ -18,6 +18,11 @@ type (
 		Height   int64
 	}
 
+	ErrBlockPruned struct {
+		Height int64
+		Base   int64
+	}
+
 	ErrAppBlockHeightTooHigh struct {
 		CoreHeight int64
 		AppHeight  int64
@@ -55,6 +60,10 @@ func (e ErrBlockHashMismatch) Error() string {
 	return cmn.Fmt("App block hash (%X) does not match core block hash (%X) for height %d", e.AppHash, e.CoreHash, e.Height)
 }
 
+func (e ErrBlockPruned) Error() string {
+	return cmn.Fmt("Block #%d has been pruned, the lowest available block is #%d", e.Height, e.Base)
+}
+
 func (e ErrAppBlockHeightTooHigh) Error() string {
 	return cmn.Fmt("App block height (%d) is higher than core (%d)", e.AppHeight, e.CoreHeight)
 }
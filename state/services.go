@@ -21,6 +21,7 @@ type Mempool interface {
 	Unlock()
 
 	Size() int
+	TxsBytes() int64
 	CheckTx(types.Tx, func(*abci.Response)) error
 	Reap(int) types.Txs
 	Update(height int64, txs types.Txs) error
@@ -38,6 +39,7 @@ type MockMempool struct {
 func (m MockMempool) Lock()                                              {}
 func (m MockMempool) Unlock()                                            {}
 func (m MockMempool) Size() int                                          { return 0 }
+func (m MockMempool) TxsBytes() int64                                    { return 0 }
 func (m MockMempool) CheckTx(tx types.Tx, cb func(*abci.Response)) error { return nil }
 func (m MockMempool) Reap(n int) types.Txs                               { return types.Txs{} }
 func (m MockMempool) Update(height int64, txs types.Txs) error           { return nil }
@@ -51,10 +53,13 @@ func (m MockMempool) EnableTxsAvailable()                                {}
 
 // BlockStoreRPC is the block store interface used by the RPC.
 type BlockStoreRPC interface {
+	Base() int64
 	Height() int64
 
 	LoadBlockMeta(height int64) *types.BlockMeta
 	LoadBlock(height int64) *types.Block
+	LoadBlockChecked(height int64) (*types.Block, error)
+	LoadBlockByHash(hash []byte) *types.Block
 	LoadBlockPart(height int64, index int) *types.Part
 
 	LoadBlockCommit(height int64) *types.Commit
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"hash/fnv"
 	"sync"
 	"time"
 
@@ -21,6 +22,20 @@ const (
 
 	// Check if the WS client is connected every
 	connectionCheckPeriod = 100 * time.Millisecond
+
+	// Ping the node with this period. After maxMissedPongs (3 by default) periods without
+	// hearing anything back, the connection is dropped and redialed.
+	pingPeriod            = 1 * time.Second
+	defaultMaxMissedPongs = 3
+
+	// Event callbacks are run by this many goroutines, each queueing up to
+	// callbackQueueSize notifications before dropping the oldest one.
+	callbackWorkers   = 4
+	callbackQueueSize = 100
+
+	// Number of most recent intervals between events the windowed min/max
+	// durations are computed over.
+	durationWindowSize = 100
 )
 
 // EventMetric exposes metrics for an event.
@@ -31,6 +46,14 @@ type EventMetric struct {
 	MinDuration int64     `json:"min_duration"`
 	MaxDuration int64     `json:"max_duration"`
 
+	// min and max over the last durationWindowSize intervals
+	WindowMinDuration int64 `json:"window_min_duration"`
+	WindowMaxDuration int64 `json:"window_max_duration"`
+
+	// ring buffer of the most recent intervals
+	durations    []int64
+	durationsIdx int
+
 	// tracks event count and rate
 	meter metrics.Meter
 
@@ -41,17 +64,69 @@ type EventMetric struct {
 	Rate15   float64 `json:"rate_15" amino:"unsafe"`
 	RateMean float64 `json:"rate_mean" amino:"unsafe"`
 
-	// so the event can have effects in the eventmeter's consumer. runs in a go
-	// routine.
+	// number of callback notifications dropped because the consumer was too
+	// slow
+	DroppedCallbacks int64 `json:"dropped_callbacks"`
+
+	// so the event can have effects in the eventmeter's consumer. runs on one
+	// of the eventmeter's callback workers, in event order.
 	callback EventCallbackFunc
 }
 
 func (metric *EventMetric) Copy() *EventMetric {
 	metricCopy := *metric
 	metricCopy.meter = metric.meter.Snapshot()
+	metricCopy.durations = nil
 	return &metricCopy
 }
 
+// reset returns a fresh metric with the same ID and callback. The meter is
+// replaced since go-metrics can't rewind one.
+func (metric *EventMetric) reset() *EventMetric {
+	metric.meter.Stop()
+	return &EventMetric{
+		ID:       metric.ID,
+		Started:  time.Now(),
+		meter:    metrics.NewMeter(),
+		callback: metric.callback,
+	}
+}
+
+// observe records an event heard at t. Durations are only tracked from the
+// second event on, since there is no interval before the first one.
+func (metric *EventMetric) observe(t time.Time) {
+	last := metric.LastHeard
+	metric.LastHeard = t
+	metric.meter.Mark(1)
+	if last.IsZero() {
+		return
+	}
+
+	dur := int64(t.Sub(last))
+	if metric.MinDuration == 0 || dur < metric.MinDuration {
+		metric.MinDuration = dur
+	}
+	if dur > metric.MaxDuration {
+		metric.MaxDuration = dur
+	}
+
+	if len(metric.durations) < durationWindowSize {
+		metric.durations = append(metric.durations, dur)
+	} else {
+		metric.durations[metric.durationsIdx] = dur
+		metric.durationsIdx = (metric.durationsIdx + 1) % durationWindowSize
+	}
+	metric.WindowMinDuration, metric.WindowMaxDuration = dur, dur
+	for _, d := range metric.durations {
+		if d < metric.WindowMinDuration {
+			metric.WindowMinDuration = d
+		}
+		if d > metric.WindowMaxDuration {
+			metric.WindowMaxDuration = d
+		}
+	}
+}
+
 // called on GetMetric
 func (metric *EventMetric) fillMetric() *EventMetric {
 	metric.Count = metric.meter.Count()
@@ -71,12 +146,42 @@ type EventCallbackFunc func(em *EventMetric, data interface{})
 type EventUnmarshalFunc func(b json.RawMessage) (string, events.EventData, error)
 
 // LatencyCallbackFunc is a closure to enable side effects from receiving a latency.
-type LatencyCallbackFunc func(meanLatencyNanoSeconds float64)
+type LatencyCallbackFunc func(latency LatencySnapshot)
+
+// LatencySnapshot summarises the ping/pong latency distribution. All values
+// are in nanoseconds.
+type LatencySnapshot struct {
+	Mean   float64 `json:"mean" amino:"unsafe"`
+	Min    int64   `json:"min"`
+	Max    int64   `json:"max"`
+	StdDev float64 `json:"stddev" amino:"unsafe"`
+	P50    float64 `json:"p50" amino:"unsafe"`
+	P95    float64 `json:"p95" amino:"unsafe"`
+	P99    float64 `json:"p99" amino:"unsafe"`
+}
+
+func newLatencySnapshot(t metrics.Timer) LatencySnapshot {
+	s := t.Snapshot()
+	ps := s.Percentiles([]float64{0.5, 0.95, 0.99})
+	return LatencySnapshot{
+		Mean:   s.Mean(),
+		Min:    s.Min(),
+		Max:    s.Max(),
+		StdDev: s.StdDev(),
+		P50:    ps[0],
+		P95:    ps[1],
+		P99:    ps[2],
+	}
+}
 
 // DisconnectCallbackFunc is a closure to notify a consumer that the connection
 // has died.
 type DisconnectCallbackFunc func()
 
+// ReconnectCallbackFunc is a closure to notify a consumer that the connection
+// has been restored and all queries have been resubscribed.
+type ReconnectCallbackFunc func()
+
 // EventMeter tracks events, reports latency and disconnects.
 type EventMeter struct {
 	wsc *client.WSClient
@@ -87,20 +192,64 @@ type EventMeter struct {
 	unmarshalEvent     EventUnmarshalFunc
 	latencyCallback    LatencyCallbackFunc
 	disconnectCallback DisconnectCallbackFunc
+	reconnectCallback  ReconnectCallbackFunc
 	subscribed         bool
+	disconnectedCh     chan struct{} // the WS client lost the connection
+
+	maxMissedPongs int
+
+	callbackQueues []chan callbackJob
 
 	quit chan struct{}
 
 	logger log.Logger
 }
 
-func NewEventMeter(addr string, unmarshalEvent EventUnmarshalFunc) *EventMeter {
-	return &EventMeter{
-		wsc:              client.NewWSClient(addr, "/websocket", client.PingPeriod(1*time.Second)),
+// callbackJob is a pending call of an EventCallbackFunc.
+type callbackJob struct {
+	query  string
+	metric *EventMetric
+	data   events.EventData
+	cb     EventCallbackFunc
+}
+
+// NewEventMeter returns a new event meter for the node at addr. You can
+// provide options to change some default values.
+func NewEventMeter(addr string, unmarshalEvent EventUnmarshalFunc, options ...func(*EventMeter)) *EventMeter {
+	callbackQueues := make([]chan callbackJob, callbackWorkers)
+	for i := range callbackQueues {
+		callbackQueues[i] = make(chan callbackJob, callbackQueueSize)
+	}
+	em := &EventMeter{
 		queryToMetricMap: make(map[string]*EventMetric),
 		unmarshalEvent:   unmarshalEvent,
+		disconnectedCh:   make(chan struct{}, 1),
+		maxMissedPongs:   defaultMaxMissedPongs,
+		callbackQueues:   callbackQueues,
 		logger:           log.NewNopLogger(),
 	}
+	for _, option := range options {
+		option(em)
+	}
+	em.wsc = client.NewWSClient(addr, "/websocket",
+		client.PingPeriod(pingPeriod),
+		client.ReadWait(time.Duration(em.maxMissedPongs)*pingPeriod),
+		client.OnDisconnect(func() {
+			select {
+			case em.disconnectedCh <- struct{}{}:
+			default:
+			}
+		}),
+	)
+	return em
+}
+
+// MaxMissedPongs lets you change the number of ping periods without hearing
+// back from the node, after which the connection is considered lost.
+func MaxMissedPongs(n int) func(*EventMeter) {
+	return func(em *EventMeter) {
+		em.maxMissedPongs = n
+	}
 }
 
 // SetLogger lets you set your own logger.
@@ -121,14 +270,15 @@ func (em *EventMeter) Start() error {
 	}
 
 	em.quit = make(chan struct{})
+	em.startCallbackRoutines()
 	go em.receiveRoutine()
-	go em.disconnectRoutine()
 
 	err := em.subscribe()
 	if err != nil {
 		return err
 	}
 	em.subscribed = true
+	go em.disconnectRoutine()
 	return nil
 }
 
@@ -152,6 +302,7 @@ func (em *EventMeter) Subscribe(query string, cb EventCallbackFunc) error {
 	}
 
 	metric := &EventMetric{
+		Started:  time.Now(),
 		meter:    metrics.NewMeter(),
 		callback: cb,
 	}
@@ -178,6 +329,45 @@ func (em *EventMeter) GetMetric(query string) (*EventMetric, error) {
 	return metric.fillMetric().Copy(), nil
 }
 
+// LatencySnapshot returns the current ping/pong latency distribution.
+func (em *EventMeter) LatencySnapshot() LatencySnapshot {
+	return newLatencySnapshot(em.wsc.PingPongLatencyTimer)
+}
+
+// GetAllMetrics fills in the latest data for every query and returns copies,
+// keyed by query.
+func (em *EventMeter) GetAllMetrics() map[string]*EventMetric {
+	em.mtx.Lock()
+	defer em.mtx.Unlock()
+	all := make(map[string]*EventMetric, len(em.queryToMetricMap))
+	for query, metric := range em.queryToMetricMap {
+		all[query] = metric.fillMetric().Copy()
+	}
+	return all
+}
+
+// ResetMetric zeroes the counters for the given query. The subscription and
+// its callback are kept.
+func (em *EventMeter) ResetMetric(query string) error {
+	em.mtx.Lock()
+	defer em.mtx.Unlock()
+	metric, ok := em.queryToMetricMap[query]
+	if !ok {
+		return fmt.Errorf("unknown query: %s", query)
+	}
+	em.queryToMetricMap[query] = metric.reset()
+	return nil
+}
+
+// ResetAll zeroes the counters for every query.
+func (em *EventMeter) ResetAll() {
+	em.mtx.Lock()
+	defer em.mtx.Unlock()
+	for query, metric := range em.queryToMetricMap {
+		em.queryToMetricMap[query] = metric.reset()
+	}
+}
+
 // RegisterLatencyCallback allows you to set latency callback.
 func (em *EventMeter) RegisterLatencyCallback(f LatencyCallbackFunc) {
 	em.mtx.Lock()
@@ -192,11 +382,23 @@ func (em *EventMeter) RegisterDisconnectCallback(f DisconnectCallbackFunc) {
 	em.disconnectCallback = f
 }
 
+// RegisterReconnectCallback allows you to set reconnect callback.
+func (em *EventMeter) RegisterReconnectCallback(f ReconnectCallbackFunc) {
+	em.mtx.Lock()
+	defer em.mtx.Unlock()
+	em.reconnectCallback = f
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 // Private
 
+// subscribe (re)subscribes to all known queries. Metrics are kept, so counts
+// and start times carry over a reconnect.
 func (em *EventMeter) subscribe() error {
-	for query, _ := range em.queryToMetricMap {
+	em.mtx.Lock()
+	defer em.mtx.Unlock()
+
+	for query := range em.queryToMetricMap {
 		if err := em.wsc.Subscribe(context.TODO(), query); err != nil {
 			return err
 		}
@@ -206,9 +408,13 @@ func (em *EventMeter) subscribe() error {
 
 func (em *EventMeter) receiveRoutine() {
 	latencyTicker := time.NewTicker(latencyPeriod)
+	defer latencyTicker.Stop()
 	for {
 		select {
-		case resp := <-em.wsc.ResponsesCh:
+		case resp, ok := <-em.wsc.ResponsesCh:
+			if !ok { // client stopped
+				return
+			}
 			if resp.Error != nil {
 				em.logger.Error("expected some event, got error", "err", resp.Error.Error())
 				continue
@@ -223,7 +429,7 @@ func (em *EventMeter) receiveRoutine() {
 			}
 		case <-latencyTicker.C:
 			if em.wsc.IsActive() {
-				em.callLatencyCallback(em.wsc.PingPongLatencyTimer.Mean())
+				em.callLatencyCallback(em.LatencySnapshot())
 			}
 		case <-em.wsc.Quit():
 			return
@@ -235,15 +441,25 @@ func (em *EventMeter) receiveRoutine() {
 
 func (em *EventMeter) disconnectRoutine() {
 	ticker := time.NewTicker(connectionCheckPeriod)
+	defer ticker.Stop()
 	for {
 		select {
+		case <-em.disconnectedCh:
+			if em.subscribed { // notify user about disconnect only once
+				em.callDisconnectCallback()
+				em.subscribed = false
+			}
 		case <-ticker.C:
 			if em.wsc.IsReconnecting() && em.subscribed { // notify user about disconnect only once
 				em.callDisconnectCallback()
 				em.subscribed = false
 			} else if !em.wsc.IsReconnecting() && !em.subscribed { // resubscribe
-				em.subscribe()
+				if err := em.subscribe(); err != nil {
+					em.logger.Error("failed to resubscribe", "err", err)
+					continue
+				}
 				em.subscribed = true
+				em.callReconnectCallback()
 			}
 		case <-em.wsc.Quit():
 			return
@@ -263,19 +479,51 @@ func (em *EventMeter) updateMetric(query string, data events.EventData) {
 		return
 	}
 
-	last := metric.LastHeard
-	metric.LastHeard = time.Now()
-	metric.meter.Mark(1)
-	dur := int64(metric.LastHeard.Sub(last))
-	if dur < metric.MinDuration {
-		metric.MinDuration = dur
+	metric.observe(time.Now())
+
+	if metric.callback != nil {
+		em.queueCallback(callbackJob{query, metric.Copy(), data, metric.callback})
 	}
-	if !last.IsZero() && dur > metric.MaxDuration {
-		metric.MaxDuration = dur
+}
+
+func (em *EventMeter) startCallbackRoutines() {
+	for _, q := range em.callbackQueues {
+		go em.callbackRoutine(q)
 	}
+}
 
-	if metric.callback != nil {
-		go metric.callback(metric.Copy(), data)
+func (em *EventMeter) callbackRoutine(q <-chan callbackJob) {
+	for {
+		select {
+		case job := <-q:
+			job.cb(job.metric, job.data)
+		case <-em.quit:
+			return
+		}
+	}
+}
+
+// queueCallback hands the job to the worker for its query, so callbacks for
+// the same query run in order. If that worker is falling behind, the oldest
+// queued notification is dropped. Must be called with em.mtx held.
+func (em *EventMeter) queueCallback(job callbackJob) {
+	h := fnv.New32a()
+	h.Write([]byte(job.query)) // nolint: errcheck
+	q := em.callbackQueues[h.Sum32()%uint32(len(em.callbackQueues))]
+	for {
+		select {
+		case q <- job:
+			return
+		default:
+		}
+
+		select {
+		case dropped := <-q:
+			if metric, ok := em.queryToMetricMap[dropped.query]; ok {
+				metric.DroppedCallbacks++
+			}
+		default:
+		}
 	}
 }
 
@@ -287,10 +535,18 @@ func (em *EventMeter) callDisconnectCallback() {
 	em.mtx.Unlock()
 }
 
-func (em *EventMeter) callLatencyCallback(meanLatencyNanoSeconds float64) {
+func (em *EventMeter) callReconnectCallback() {
+	em.mtx.Lock()
+	if em.reconnectCallback != nil {
+		go em.reconnectCallback()
+	}
+	em.mtx.Unlock()
+}
+
+func (em *EventMeter) callLatencyCallback(latency LatencySnapshot) {
 	em.mtx.Lock()
 	if em.latencyCallback != nil {
-		go em.latencyCallback(meanLatencyNanoSeconds)
+		go em.latencyCallback(latency)
 	}
 	em.mtx.Unlock()
 }
@@ -0,0 +1,350 @@
+package eventmeter
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	metrics "github.com/rcrowley/go-metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/libs/events"
+	types "github.com/tendermint/tendermint/rpc/lib/types"
+)
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+}
+
+// stubHandler answers every websocket message with an empty result.
+type stubHandler struct {
+	ignorePings bool
+}
+
+func (h *stubHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		panic(err)
+	}
+	defer conn.Close() // nolint: errcheck
+	if h.ignorePings {
+		conn.SetPingHandler(func(string) error { return nil })
+	}
+	for {
+		messageType, _, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		respBytes, _ := json.Marshal(types.RPCResponse{Result: json.RawMessage(`{}`)})
+		if err := conn.WriteMessage(messageType, respBytes); err != nil {
+			return
+		}
+	}
+}
+
+func nopUnmarshal(b json.RawMessage) (string, events.EventData, error) {
+	return "", nil, nil
+}
+
+func TestEventMeterStopReleasesGoroutines(t *testing.T) {
+	// go-metrics starts a single shared goroutine on first use
+	metrics.NewMeter()
+
+	s := httptest.NewServer(&stubHandler{})
+	baseline := runtime.NumGoroutine()
+
+	for i := 0; i < 3; i++ {
+		em := NewEventMeter(s.Listener.Addr().String(), nopUnmarshal)
+		require.NoError(t, em.Start())
+		em.Stop()
+	}
+
+	s.Close()
+	deadline := time.Now().Add(2 * time.Second)
+	for runtime.NumGoroutine() > baseline && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	require.True(t, runtime.NumGoroutine() <= baseline, "goroutines: %d, baseline: %d", runtime.NumGoroutine(), baseline)
+}
+
+func TestEventMeterDisconnectsWhenPongsStop(t *testing.T) {
+	s := httptest.NewServer(&stubHandler{ignorePings: true})
+	defer s.Close()
+
+	em := NewEventMeter(s.Listener.Addr().String(), nopUnmarshal, MaxMissedPongs(1))
+	disconnected := make(chan struct{}, 1)
+	em.RegisterDisconnectCallback(func() { disconnected <- struct{}{} })
+	require.NoError(t, em.Start())
+	defer em.Stop()
+
+	select {
+	case <-disconnected:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a disconnect once the node stopped answering pings")
+	}
+}
+
+// eventHandler answers every websocket message (ie. a subscription) with an
+// event for testQuery, and drops the first connection after doing so.
+type eventHandler struct {
+	mtx   sync.Mutex
+	conns int
+}
+
+const testQuery = "tm.event = 'Test'"
+
+func (h *eventHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		panic(err)
+	}
+	defer conn.Close() // nolint: errcheck
+
+	h.mtx.Lock()
+	h.conns++
+	first := h.conns == 1
+	h.mtx.Unlock()
+
+	for {
+		messageType, _, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		result, _ := json.Marshal(map[string]string{"query": testQuery})
+		respBytes, _ := json.Marshal(types.RPCResponse{Result: result})
+		if err := conn.WriteMessage(messageType, respBytes); err != nil {
+			return
+		}
+		if first {
+			return
+		}
+	}
+}
+
+func queryUnmarshal(b json.RawMessage) (string, events.EventData, error) {
+	var ev struct {
+		Query string `json:"query"`
+	}
+	if err := json.Unmarshal(b, &ev); err != nil {
+		return "", nil, err
+	}
+	return ev.Query, nil, nil
+}
+
+func TestEventMeterReconnectKeepsMetrics(t *testing.T) {
+	s := httptest.NewServer(&eventHandler{})
+	defer s.Close()
+
+	em := NewEventMeter(s.Listener.Addr().String(), queryUnmarshal)
+	disconnected := make(chan struct{}, 1)
+	reconnected := make(chan struct{}, 1)
+	em.RegisterDisconnectCallback(func() { disconnected <- struct{}{} })
+	em.RegisterReconnectCallback(func() { reconnected <- struct{}{} })
+	require.NoError(t, em.Start())
+	defer em.Stop()
+
+	require.NoError(t, em.Subscribe(testQuery, nil))
+	started, err := em.GetMetric(testQuery)
+	require.NoError(t, err)
+
+	for _, ch := range []chan struct{}{disconnected, reconnected} {
+		select {
+		case <-ch:
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for the connection to drop and come back")
+		}
+	}
+
+	// the resubscription yields a second event on the same metric
+	deadline := time.Now().Add(time.Second)
+	var metric *EventMetric
+	for time.Now().Before(deadline) {
+		metric, err = em.GetMetric(testQuery)
+		require.NoError(t, err)
+		if metric.Count == 2 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.EqualValues(t, 2, metric.Count)
+	assert.Equal(t, started.Started, metric.Started)
+}
+
+func TestLatencySnapshot(t *testing.T) {
+	timer := metrics.NewTimer()
+	for i := 1; i <= 100; i++ {
+		timer.Update(time.Duration(i) * time.Millisecond)
+	}
+
+	s := newLatencySnapshot(timer)
+	ms := float64(time.Millisecond)
+	assert.Equal(t, int64(time.Millisecond), s.Min)
+	assert.Equal(t, int64(100*time.Millisecond), s.Max)
+	assert.InDelta(t, 50.5*ms, s.Mean, 1)
+	assert.InDelta(t, 50.5*ms, s.P50, 1)
+	assert.InDelta(t, 95.95*ms, s.P95, 1)
+	assert.InDelta(t, 99.99*ms, s.P99, 1)
+	assert.InDelta(t, 28.87*ms, s.StdDev, 0.01*ms)
+}
+
+func TestEventMeterResetAndGetAll(t *testing.T) {
+	em := NewEventMeter("127.0.0.1:0", queryUnmarshal)
+	em.quit = make(chan struct{})
+	em.startCallbackRoutines()
+	defer close(em.quit)
+	called := make(chan struct{}, 100)
+	cb := func(*EventMetric, interface{}) { called <- struct{}{} }
+	em.queryToMetricMap["a"] = &EventMetric{ID: "a", meter: metrics.NewMeter(), callback: cb}
+	em.queryToMetricMap["b"] = &EventMetric{ID: "b", meter: metrics.NewMeter()}
+
+	em.updateMetric("a", nil)
+	em.updateMetric("a", nil)
+	em.updateMetric("b", nil)
+	<-called
+
+	all := em.GetAllMetrics()
+	require.Len(t, all, 2)
+	assert.EqualValues(t, 2, all["a"].Count)
+	assert.EqualValues(t, 1, all["b"].Count)
+
+	require.NoError(t, em.ResetMetric("a"))
+	assert.Error(t, em.ResetMetric("c"))
+	all = em.GetAllMetrics()
+	assert.EqualValues(t, 0, all["a"].Count)
+	assert.True(t, all["a"].LastHeard.IsZero())
+	assert.EqualValues(t, 1, all["b"].Count)
+
+	// the callback survives a reset
+	em.updateMetric("a", nil)
+	<-called
+
+	em.ResetAll()
+	for query, metric := range em.GetAllMetrics() {
+		assert.EqualValues(t, 0, metric.Count, query)
+	}
+}
+
+func TestEventMeterResetConcurrently(t *testing.T) {
+	em := NewEventMeter("127.0.0.1:0", queryUnmarshal)
+	em.queryToMetricMap["a"] = &EventMetric{ID: "a", meter: metrics.NewMeter()}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				switch i {
+				case 0:
+					em.updateMetric("a", nil)
+				case 1:
+					em.ResetAll()
+				case 2:
+					_ = em.ResetMetric("a")
+				default:
+					em.GetAllMetrics()
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestEventMeterCallbackFloodIsBounded(t *testing.T) {
+	em := NewEventMeter("127.0.0.1:0", queryUnmarshal)
+	em.quit = make(chan struct{})
+	em.startCallbackRoutines()
+	defer close(em.quit)
+
+	release := make(chan struct{})
+	var mtx sync.Mutex
+	var seen []int
+	cb := func(_ *EventMetric, data interface{}) {
+		<-release // a consumer stuck behind a lock
+		mtx.Lock()
+		seen = append(seen, data.(int))
+		mtx.Unlock()
+	}
+	em.queryToMetricMap["a"] = &EventMetric{ID: "a", meter: metrics.NewMeter(), callback: cb}
+
+	baseline := runtime.NumGoroutine()
+	const numEvents = 10000
+	for i := 0; i < numEvents; i++ {
+		em.updateMetric("a", i)
+	}
+	assert.True(t, runtime.NumGoroutine() <= baseline, "goroutines: %d, baseline: %d", runtime.NumGoroutine(), baseline)
+
+	close(release)
+	metric, err := em.GetMetric("a")
+	require.NoError(t, err)
+	assert.EqualValues(t, numEvents, metric.Count)
+	assert.True(t, metric.DroppedCallbacks >= numEvents-callbackQueueSize-1, "dropped: %d", metric.DroppedCallbacks)
+
+	// the newest notifications made it through, in order
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		mtx.Lock()
+		n := len(seen)
+		mtx.Unlock()
+		if int64(n) == numEvents-metric.DroppedCallbacks {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	mtx.Lock()
+	defer mtx.Unlock()
+	require.EqualValues(t, numEvents-metric.DroppedCallbacks, len(seen))
+	assert.Equal(t, numEvents-1, seen[len(seen)-1])
+	for i := 1; i < len(seen); i++ {
+		assert.True(t, seen[i-1] < seen[i], "out of order at %d", i)
+	}
+}
+
+func TestEventMetricDurations(t *testing.T) {
+	metric := &EventMetric{meter: metrics.NewMeter()}
+	start := time.Now()
+
+	// the first event has no interval
+	metric.observe(start)
+	assert.EqualValues(t, 0, metric.MinDuration)
+	assert.EqualValues(t, 0, metric.MaxDuration)
+	assert.EqualValues(t, 0, metric.WindowMinDuration)
+	assert.EqualValues(t, 0, metric.WindowMaxDuration)
+
+	now := start
+	tick := func(d time.Duration) {
+		now = now.Add(d)
+		metric.observe(now)
+	}
+
+	// a stall followed by a full window of steady events
+	tick(time.Second)
+	tick(time.Minute)
+	for i := 0; i < durationWindowSize-2; i++ {
+		tick(2 * time.Second)
+	}
+	assert.EqualValues(t, time.Second, metric.MinDuration)
+	assert.EqualValues(t, time.Minute, metric.MaxDuration)
+	assert.EqualValues(t, time.Second, metric.WindowMinDuration)
+	assert.EqualValues(t, time.Minute, metric.WindowMaxDuration)
+
+	// the 1s interval rolls out of the window, then the stall does
+	tick(2 * time.Second)
+	assert.EqualValues(t, 2*time.Second, metric.WindowMinDuration)
+	assert.EqualValues(t, time.Minute, metric.WindowMaxDuration)
+	tick(2 * time.Second)
+	assert.EqualValues(t, 2*time.Second, metric.WindowMinDuration)
+	assert.EqualValues(t, 2*time.Second, metric.WindowMaxDuration)
+
+	// all-time values are unaffected
+	assert.EqualValues(t, time.Second, metric.MinDuration)
+	assert.EqualValues(t, time.Minute, metric.MaxDuration)
+	assert.EqualValues(t, durationWindowSize+3, metric.meter.Count())
+}
@@ -16,9 +16,13 @@ var logger = log.NewNopLogger()
 func main() {
 	var listenAddr string
 	var noton bool
+	var slowRoundThreshold int
+	var participationThreshold float64
 
 	flag.StringVar(&listenAddr, "listen-addr", "tcp://0.0.0.0:26670", "HTTP and Websocket server listen address")
 	flag.BoolVar(&noton, "no-ton", false, "Do not show ton (table of nodes)")
+	flag.IntVar(&slowRoundThreshold, "slow-round-threshold", 3, "Flag consensus as slow once the round for a height exceeds this number")
+	flag.Float64Var(&participationThreshold, "participation-threshold", 0.9, "Report validators signing less than this share of recent blocks")
 
 	flag.Usage = func() {
 		fmt.Println(`Tendermint monitor watches over one or more Tendermint core
@@ -48,7 +52,7 @@ Examples:
 		logger = log.NewTMLogger(log.NewSyncWriter(os.Stdout))
 	}
 
-	m := startMonitor(flag.Arg(0))
+	m := startMonitor(flag.Arg(0), slowRoundThreshold, participationThreshold)
 
 	startRPC(listenAddr, m, logger)
 
@@ -66,8 +70,11 @@ Examples:
 	})
 }
 
-func startMonitor(endpoints string) *monitor.Monitor {
-	m := monitor.NewMonitor()
+func startMonitor(endpoints string, slowRoundThreshold int, participationThreshold float64) *monitor.Monitor {
+	m := monitor.NewMonitor(
+		monitor.SetSlowRoundThreshold(slowRoundThreshold),
+		monitor.SetParticipationThreshold(participationThreshold),
+	)
 	m.SetLogger(logger.With("component", "monitor"))
 
 	for _, e := range strings.Split(endpoints, ",") {
@@ -7,12 +7,14 @@ import (
 	"github.com/tendermint/go-amino"
 	"github.com/tendermint/tendermint/libs/log"
 	em "github.com/tendermint/tendermint/tools/tm-monitor/eventmeter"
+	tmtypes "github.com/tendermint/tendermint/types"
 )
 
 type EventMeter struct {
 	latencyCallback    em.LatencyCallbackFunc
 	disconnectCallback em.DisconnectCallbackFunc
-	eventCallback      em.EventCallbackFunc
+	reconnectCallback  em.ReconnectCallbackFunc
+	eventCallbacks     map[string]em.EventCallbackFunc
 }
 
 func (e *EventMeter) Start() error                                      { return nil }
@@ -22,23 +24,44 @@ func (e *EventMeter) RegisterLatencyCallback(cb em.LatencyCallbackFunc) { e.late
 func (e *EventMeter) RegisterDisconnectCallback(cb em.DisconnectCallbackFunc) {
 	e.disconnectCallback = cb
 }
+func (e *EventMeter) RegisterReconnectCallback(cb em.ReconnectCallbackFunc) {
+	e.reconnectCallback = cb
+}
 func (e *EventMeter) Subscribe(query string, cb em.EventCallbackFunc) error {
-	e.eventCallback = cb
+	if e.eventCallbacks == nil {
+		e.eventCallbacks = make(map[string]em.EventCallbackFunc)
+	}
+	e.eventCallbacks[query] = cb
 	return nil
 }
 func (e *EventMeter) Unsubscribe(query string) error {
-	e.eventCallback = nil
+	delete(e.eventCallbacks, query)
 	return nil
 }
 
 func (e *EventMeter) Call(callback string, args ...interface{}) {
 	switch callback {
 	case "latencyCallback":
-		e.latencyCallback(args[0].(float64))
+		e.latencyCallback(args[0].(em.LatencySnapshot))
 	case "disconnectCallback":
 		e.disconnectCallback()
+	case "reconnectCallback":
+		e.reconnectCallback()
 	case "eventCallback":
-		e.eventCallback(args[0].(*em.EventMetric), args[1])
+		e.eventCallbacks[queryFor(args[1])](args[0].(*em.EventMetric), args[1])
+	}
+}
+
+// queryFor returns the query a node subscribes with to receive the given
+// event data.
+func queryFor(data interface{}) string {
+	switch data.(type) {
+	case tmtypes.EventDataRoundState:
+		return tmtypes.EventQueryNewRoundStep.String()
+	case tmtypes.EventDataVote:
+		return tmtypes.EventQueryVote.String()
+	default:
+		return tmtypes.EventQueryNewBlock.String()
 	}
 }
 
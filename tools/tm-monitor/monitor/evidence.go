@@ -0,0 +1,90 @@
+package monitor
+
+import (
+	"bytes"
+	"time"
+
+	cmn "github.com/tendermint/tendermint/libs/common"
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+// votes older than this many heights are forgotten
+const voteRetainHeights = 3
+
+// DoubleSignEvidence describes a validator which signed two different votes
+// of the same type for the same height and round.
+type DoubleSignEvidence struct {
+	Validator   cmn.HexBytes   `json:"validator"`
+	Height      int64          `json:"height"`
+	Round       int            `json:"round"`
+	Type        byte           `json:"type"`
+	BlockHashes []cmn.HexBytes `json:"block_hashes"`
+	Timestamps  []time.Time    `json:"timestamps"`
+}
+
+type voteKey struct {
+	height    int64
+	round     int
+	type_     byte
+	validator string
+}
+
+type seenVote struct {
+	blockHash cmn.HexBytes
+	timestamp time.Time
+	reported  bool
+}
+
+// NewVote is called when a node sees a vote. It returns the evidence if the
+// vote conflicts with a vote seen earlier. Each equivocation is reported only
+// once.
+func (n *Network) NewVote(v *tmtypes.Vote) *DoubleSignEvidence {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if v.Height < n.Height-voteRetainHeights {
+		return nil
+	}
+
+	key := voteKey{v.Height, v.Round, v.Type, string(v.ValidatorAddress)}
+	seen, ok := n.votes[key]
+	if !ok {
+		n.votes[key] = &seenVote{blockHash: v.BlockID.Hash, timestamp: v.Timestamp}
+		return nil
+	}
+	if seen.reported || bytes.Equal(seen.blockHash, v.BlockID.Hash) {
+		return nil
+	}
+
+	seen.reported = true
+	ev := &DoubleSignEvidence{
+		Validator:   v.ValidatorAddress,
+		Height:      v.Height,
+		Round:       v.Round,
+		Type:        v.Type,
+		BlockHashes: []cmn.HexBytes{seen.blockHash, v.BlockID.Hash},
+		Timestamps:  []time.Time{seen.timestamp, v.Timestamp},
+	}
+	n.Evidence = append(n.Evidence, ev)
+	return ev
+}
+
+// GetEvidence returns all double signing incidents detected so far.
+func (n *Network) GetEvidence() []*DoubleSignEvidence {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	evidence := make([]*DoubleSignEvidence, len(n.Evidence))
+	copy(evidence, n.Evidence)
+	return evidence
+}
+
+// pruneVotes forgets votes more than voteRetainHeights below the current
+// height. Must be called with the lock held.
+func (n *Network) pruneVotes() {
+	for k := range n.votes {
+		if k.height < n.Height-voteRetainHeights {
+			delete(n.votes, k)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package monitor_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cmn "github.com/tendermint/tendermint/libs/common"
+	monitor "github.com/tendermint/tendermint/tools/tm-monitor/monitor"
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+func makeVote(addr []byte, height int64, round int, type_ byte, hash []byte) *tmtypes.Vote {
+	return &tmtypes.Vote{
+		ValidatorAddress: addr,
+		Height:           height,
+		Round:            round,
+		Type:             type_,
+		Timestamp:        time.Now(),
+		BlockID:          tmtypes.BlockID{Hash: hash},
+	}
+}
+
+func TestNetworkDetectsDoubleSign(t *testing.T) {
+	n := monitor.NewNetwork()
+	val := []byte("validator")
+
+	// the same vote seen through several nodes is fine
+	assert.Nil(t, n.NewVote(makeVote(val, 1, 0, tmtypes.VoteTypePrevote, []byte("A"))))
+	assert.Nil(t, n.NewVote(makeVote(val, 1, 0, tmtypes.VoteTypePrevote, []byte("A"))))
+	// as are votes of another type, round or validator
+	assert.Nil(t, n.NewVote(makeVote(val, 1, 0, tmtypes.VoteTypePrecommit, []byte("B"))))
+	assert.Nil(t, n.NewVote(makeVote(val, 1, 1, tmtypes.VoteTypePrevote, []byte("B"))))
+	assert.Nil(t, n.NewVote(makeVote([]byte("other"), 1, 0, tmtypes.VoteTypePrevote, []byte("B"))))
+
+	ev := n.NewVote(makeVote(val, 1, 0, tmtypes.VoteTypePrevote, []byte("B")))
+	require.NotNil(t, ev)
+	assert.Equal(t, cmn.HexBytes(val), ev.Validator)
+	assert.Equal(t, int64(1), ev.Height)
+	assert.Equal(t, 0, ev.Round)
+	assert.Equal(t, []cmn.HexBytes{cmn.HexBytes("A"), cmn.HexBytes("B")}, ev.BlockHashes)
+	assert.Len(t, ev.Timestamps, 2)
+
+	// reported exactly once
+	assert.Nil(t, n.NewVote(makeVote(val, 1, 0, tmtypes.VoteTypePrevote, []byte("B"))))
+	assert.Nil(t, n.NewVote(makeVote(val, 1, 0, tmtypes.VoteTypePrevote, []byte("C"))))
+	assert.Equal(t, []*monitor.DoubleSignEvidence{ev}, n.GetEvidence())
+}
+
+func TestNetworkForgetsOldVotes(t *testing.T) {
+	n := monitor.NewNetwork()
+	val := []byte("validator")
+
+	assert.Nil(t, n.NewVote(makeVote(val, 1, 0, tmtypes.VoteTypePrevote, []byte("A"))))
+	n.NewBlock(tmtypes.Header{Height: 10})
+
+	// too old to tell
+	assert.Nil(t, n.NewVote(makeVote(val, 1, 0, tmtypes.VoteTypePrevote, []byte("B"))))
+	assert.Empty(t, n.GetEvidence())
+}
@@ -1,6 +1,8 @@
 package monitor
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"math/rand"
 	"sync"
@@ -29,6 +31,12 @@ type Monitor struct {
 
 	recalculateNetworkUptimeEvery time.Duration
 	numValidatorsUpdateInterval   time.Duration
+	slowRoundThreshold            int
+	participationThreshold        float64
+
+	snapshotMtx  sync.Mutex
+	generation   int64
+	lastSnapshot []byte // JSON of the last snapshot, to detect changes
 
 	logger log.Logger
 }
@@ -46,6 +54,8 @@ func NewMonitor(options ...func(*Monitor)) *Monitor {
 		nodeQuit:                      make(map[string]chan struct{}),
 		recalculateNetworkUptimeEvery: 10 * time.Second,
 		numValidatorsUpdateInterval:   5 * time.Second,
+		slowRoundThreshold:            defaultSlowRoundThreshold,
+		participationThreshold:        defaultParticipationThreshold,
 		logger: log.NewNopLogger(),
 	}
 
@@ -53,6 +63,9 @@ func NewMonitor(options ...func(*Monitor)) *Monitor {
 		option(m)
 	}
 
+	m.Network.SetSlowRoundThreshold(m.slowRoundThreshold)
+	m.Network.SetParticipationThreshold(m.participationThreshold)
+
 	return m
 }
 
@@ -70,6 +83,22 @@ func SetNumValidatorsUpdateInterval(d time.Duration) func(m *Monitor) {
 	}
 }
 
+// SetSlowRoundThreshold lets you change the round number above which
+// consensus is considered slow.
+func SetSlowRoundThreshold(r int) func(m *Monitor) {
+	return func(m *Monitor) {
+		m.slowRoundThreshold = r
+	}
+}
+
+// SetParticipationThreshold lets you change the participation rate below
+// which a validator is reported.
+func SetParticipationThreshold(r float64) func(m *Monitor) {
+	return func(m *Monitor) {
+		m.participationThreshold = r
+	}
+}
+
 // SetLogger lets you set your own logger
 func (m *Monitor) SetLogger(l log.Logger) {
 	m.logger = l
@@ -88,6 +117,12 @@ func (m *Monitor) Monitor(n *Node) error {
 	n.SendBlockLatenciesTo(blockLatencyCh)
 	disconnectCh := make(chan bool, 10)
 	n.NotifyAboutDisconnects(disconnectCh)
+	roundCh := make(chan tmtypes.EventDataRoundState, 10)
+	n.SendRoundsTo(roundCh)
+	voteCh := make(chan *tmtypes.Vote, 100)
+	n.SendVotesTo(voteCh)
+	participationCh := make(chan float64, 10)
+	n.SendParticipationRatesTo(participationCh)
 
 	if err := n.Start(); err != nil {
 		return err
@@ -96,7 +131,7 @@ func (m *Monitor) Monitor(n *Node) error {
 	m.Network.NewNode(n.Name)
 
 	m.nodeQuit[n.Name] = make(chan struct{})
-	go m.listen(n.Name, blockCh, blockLatencyCh, disconnectCh, m.nodeQuit[n.Name])
+	go m.listen(n.Name, blockCh, blockLatencyCh, disconnectCh, roundCh, voteCh, participationCh, m.nodeQuit[n.Name])
 
 	return nil
 }
@@ -138,14 +173,52 @@ func (m *Monitor) NodeIsOnline(name string) {
 	_, node := m.NodeByName(name)
 	if nil != node {
 		if online, ok := m.Network.nodeStatusMap[name]; ok && online {
-			m.mtx.Lock()
-			node.Online = online
-			m.mtx.Unlock()
+			node.setOnline(online)
 		}
 	}
 
 }
 
+// Snapshot is a copy of the network and per node statistics, taken at some
+// generation.
+type Snapshot struct {
+	Generation  int64    `json:"generation"`
+	NotModified bool     `json:"not_modified"`
+	Network     *Network `json:"network,omitempty"`
+	Nodes       []*Node  `json:"nodes,omitempty"`
+}
+
+// Snapshot returns a copy of the network and per node statistics. The
+// generation is increased every time the statistics differ from the previous
+// snapshot. If it's still equal to since, only the generation is returned and
+// NotModified is set, so callers polling for changes can skip the rest.
+func (m *Monitor) Snapshot(since int64) (*Snapshot, error) {
+	network := m.Network.Copy()
+	m.mtx.Lock()
+	nodes := make([]*Node, len(m.Nodes))
+	for i, n := range m.Nodes {
+		nodes[i] = n.Copy()
+	}
+	m.mtx.Unlock()
+
+	bz, err := json.Marshal(Snapshot{Network: network, Nodes: nodes})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal snapshot")
+	}
+
+	m.snapshotMtx.Lock()
+	defer m.snapshotMtx.Unlock()
+
+	if !bytes.Equal(bz, m.lastSnapshot) {
+		m.generation++
+		m.lastSnapshot = bz
+	}
+	if since == m.generation {
+		return &Snapshot{Generation: m.generation, NotModified: true}, nil
+	}
+	return &Snapshot{Generation: m.generation, Network: network, Nodes: nodes}, nil
+}
+
 // Start starts the monitor's routines: recalculating network uptime and
 // updating number of validators.
 func (m *Monitor) Start() error {
@@ -165,7 +238,7 @@ func (m *Monitor) Stop() {
 }
 
 // main loop where we listen for events from the node
-func (m *Monitor) listen(nodeName string, blockCh <-chan tmtypes.Header, blockLatencyCh <-chan float64, disconnectCh <-chan bool, quit <-chan struct{}) {
+func (m *Monitor) listen(nodeName string, blockCh <-chan tmtypes.Header, blockLatencyCh <-chan float64, disconnectCh <-chan bool, roundCh <-chan tmtypes.EventDataRoundState, voteCh <-chan *tmtypes.Vote, participationCh <-chan float64, quit <-chan struct{}) {
 	logger := m.logger.With("node", nodeName)
 
 	for {
@@ -180,6 +253,18 @@ func (m *Monitor) listen(nodeName string, blockCh <-chan tmtypes.Header, blockLa
 			m.Network.NewBlockLatency(l)
 			m.Network.NodeIsOnline(nodeName)
 			m.NodeIsOnline(nodeName)
+		case rs := <-roundCh:
+			if m.Network.NewRound(rs.Height, rs.Round) {
+				logger.Info("event", "consensus_slow", "height", rs.Height, "round", rs.Round)
+			}
+		case v := <-voteCh:
+			if ev := m.Network.NewVote(v); ev != nil {
+				logger.Error("event", "double_sign", "validator", ev.Validator, "height", ev.Height, "round", ev.Round, "type", ev.Type)
+			}
+		case rate := <-participationCh:
+			if m.Network.NewParticipationRate(nodeName, rate) {
+				logger.Info("event", "low_participation", "rate", rate)
+			}
 		case disconnected := <-disconnectCh:
 			if disconnected {
 				m.Network.NodeIsDown(nodeName)
@@ -10,6 +10,7 @@ import (
 	"github.com/tendermint/go-amino"
 	"github.com/tendermint/tendermint/crypto/ed25519"
 	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	em "github.com/tendermint/tendermint/tools/tm-monitor/eventmeter"
 	mock "github.com/tendermint/tendermint/tools/tm-monitor/mock"
 	monitor "github.com/tendermint/tendermint/tools/tm-monitor/monitor"
 	tmtypes "github.com/tendermint/tendermint/types"
@@ -46,6 +47,61 @@ func TestMonitorRecalculatesNetworkUptime(t *testing.T) {
 	assert.True(t, m.Network.Uptime() < 100.0, "Uptime should be less than 100%")
 }
 
+func TestMonitorFlagsSlowConsensus(t *testing.T) {
+	m := startMonitor(t)
+	defer m.Stop()
+
+	n, emMock := createValidatorNode(t)
+	m.Monitor(n)
+
+	emMock.Call("eventCallback", &em.EventMetric{}, tmtypes.EventDataRoundState{Height: 1, Round: 4})
+	time.Sleep(100 * time.Millisecond)
+	assert.True(t, m.Network.SlowConsensus)
+
+	emMock.Call("eventCallback", &em.EventMetric{}, tmtypes.EventDataNewBlock{Block: &tmtypes.Block{Header: tmtypes.Header{Height: 1}}})
+	time.Sleep(100 * time.Millisecond)
+	assert.False(t, m.Network.SlowConsensus)
+}
+
+func TestMonitorSnapshot(t *testing.T) {
+	m := startMonitor(t)
+	defer m.Stop()
+
+	n1, emMock := createValidatorNode(t)
+	m.Monitor(n1)
+	n2, _ := createValidatorNodeAt(t, "tcp://127.0.0.2:26657")
+	m.Monitor(n2)
+
+	s, err := m.Snapshot(0)
+	require.NoError(t, err)
+	assert.False(t, s.NotModified)
+	require.NotNil(t, s.Network)
+	assert.Equal(t, 2, s.Network.NumNodesMonitored)
+	require.Len(t, s.Nodes, 2)
+	names := []string{s.Nodes[0].Name, s.Nodes[1].Name}
+	assert.Contains(t, names, n1.Name)
+	assert.Contains(t, names, n2.Name)
+
+	// nothing changed
+	s2, err := m.Snapshot(s.Generation)
+	require.NoError(t, err)
+	assert.True(t, s2.NotModified)
+	assert.Equal(t, s.Generation, s2.Generation)
+	assert.Nil(t, s2.Network)
+	assert.Nil(t, s2.Nodes)
+
+	// the snapshot is a copy
+	emMock.Call("eventCallback", &em.EventMetric{}, tmtypes.EventDataNewBlock{Block: &tmtypes.Block{Header: tmtypes.Header{Height: 1}}})
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, int64(0), s.Network.Height)
+
+	s3, err := m.Snapshot(s.Generation)
+	require.NoError(t, err)
+	assert.False(t, s3.NotModified)
+	assert.True(t, s3.Generation > s.Generation)
+	assert.Equal(t, int64(1), s3.Network.Height)
+}
+
 func startMonitor(t *testing.T) *monitor.Monitor {
 	m := monitor.NewMonitor(
 		monitor.SetNumValidatorsUpdateInterval(200*time.Millisecond),
@@ -57,6 +113,10 @@ func startMonitor(t *testing.T) *monitor.Monitor {
 }
 
 func createValidatorNode(t *testing.T) (n *monitor.Node, emMock *mock.EventMeter) {
+	return createValidatorNodeAt(t, "tcp://127.0.0.1:26657")
+}
+
+func createValidatorNodeAt(t *testing.T, rpcAddr string) (n *monitor.Node, emMock *mock.EventMeter) {
 	emMock = &mock.EventMeter{}
 
 	stubs := make(map[string]interface{})
@@ -67,6 +127,6 @@ func createValidatorNode(t *testing.T) (n *monitor.Node, emMock *mock.EventMeter
 	rpcClientMock := &mock.RpcClient{Stubs: stubs}
 	rpcClientMock.SetCodec(cdc)
 
-	n = monitor.NewNodeWithEventMeterAndRpcClient("tcp://127.0.0.1:26657", emMock, rpcClientMock)
+	n = monitor.NewNodeWithEventMeterAndRpcClient(rpcAddr, emMock, rpcClientMock)
 	return
 }
@@ -31,9 +31,24 @@ const (
 	Dead = Health(2)
 )
 
+// defaultSlowRoundThreshold is the round number above which consensus for a
+// height is considered slow.
+const defaultSlowRoundThreshold = 3
+
+// defaultParticipationThreshold is the participation rate below which a
+// validator is reported.
+const defaultParticipationThreshold = 0.9
+
 // Common statistics for network of nodes
 type Network struct {
 	Height int64 `json:"height"`
+	Round  int   `json:"round"` // highest round seen for the height being decided
+
+	// SlowConsensus is set once Round exceeds the slow round threshold and
+	// cleared when the height advances.
+	SlowConsensus      bool `json:"slow_consensus"`
+	slowRoundThreshold int
+	roundHeight        int64
 
 	AvgBlockTime      float64 `json:"avg_block_time" amino:"unsafe"` // ms (avg over last minute)
 	blockTimeMeter    metrics.Meter
@@ -48,8 +63,18 @@ type Network struct {
 
 	Health Health `json:"health"`
 
+	// MinParticipationRate is the lowest participation rate among the
+	// monitored validators.
+	MinParticipationRate   float64 `json:"min_participation_rate" amino:"unsafe"`
+	participationThreshold float64
+	participationRates     map[string]float64
+
 	UptimeData *UptimeData `json:"uptime_data"`
 
+	// Evidence lists validators caught double signing.
+	Evidence []*DoubleSignEvidence `json:"evidence"`
+	votes    map[voteKey]*seenVote
+
 	nodeStatusMap map[string]bool
 
 	mu sync.Mutex
@@ -65,10 +90,23 @@ func NewNetwork() *Network {
 			StartTime: time.Now(),
 			Uptime:    100.0,
 		},
-		nodeStatusMap: make(map[string]bool),
+		votes:                  make(map[voteKey]*seenVote),
+		MinParticipationRate:   1.0,
+		participationThreshold: defaultParticipationThreshold,
+		participationRates:     make(map[string]float64),
+		nodeStatusMap:          make(map[string]bool),
+		slowRoundThreshold:     defaultSlowRoundThreshold,
 	}
 }
 
+// SetSlowRoundThreshold lets you change the round number above which
+// consensus is considered slow.
+func (n *Network) SetSlowRoundThreshold(r int) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.slowRoundThreshold = r
+}
+
 func (n *Network) NewBlock(b tmtypes.Header) {
 	n.mu.Lock()
 	defer n.mu.Unlock()
@@ -78,6 +116,11 @@ func (n *Network) NewBlock(b tmtypes.Header) {
 	}
 
 	n.Height = b.Height
+	if n.roundHeight <= b.Height {
+		n.Round = 0
+		n.SlowConsensus = false
+	}
+	n.pruneVotes()
 
 	n.blockTimeMeter.Mark(1)
 	if n.blockTimeMeter.Rate1() > 0.0 {
@@ -89,6 +132,63 @@ func (n *Network) NewBlock(b tmtypes.Header) {
 	n.AvgTxThroughput = n.txThroughputMeter.Rate1()
 }
 
+// NewRound is called when a node enters a new round step. It returns true if
+// consensus has just become slow, i.e. the round for the height being decided
+// has exceeded the slow round threshold.
+func (n *Network) NewRound(height int64, round int) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	// height already committed or an old round
+	if height <= n.Height || height < n.roundHeight ||
+		(height == n.roundHeight && round <= n.Round) {
+		return false
+	}
+
+	if height > n.roundHeight {
+		n.roundHeight = height
+		n.SlowConsensus = false
+	}
+	n.Round = round
+
+	if !n.SlowConsensus && n.Round > n.slowRoundThreshold {
+		n.SlowConsensus = true
+		return true
+	}
+	return false
+}
+
+// SetParticipationThreshold lets you change the participation rate below
+// which a validator is reported.
+func (n *Network) SetParticipationThreshold(r float64) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.participationThreshold = r
+}
+
+// NewParticipationRate is called when the participation rate of the given
+// validator changes. It returns true if the rate has just fallen below the
+// participation threshold.
+func (n *Network) NewParticipationRate(name string, rate float64) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	prev, ok := n.participationRates[name]
+	n.participationRates[name] = rate
+	n.updateMinParticipationRate()
+
+	return rate < n.participationThreshold && (!ok || prev >= n.participationThreshold)
+}
+
+func (n *Network) updateMinParticipationRate() {
+	n.MinParticipationRate = 1.0
+	for _, rate := range n.participationRates {
+		if rate < n.MinParticipationRate {
+			n.MinParticipationRate = rate
+		}
+	}
+}
+
 func (n *Network) NewBlockLatency(l float64) {
 	n.mu.Lock()
 	defer n.mu.Unlock()
@@ -148,6 +248,11 @@ func (n *Network) NewNode(name string) {
 func (n *Network) NodeDeleted(name string) {
 	n.NumNodesMonitored--
 	n.NumNodesMonitoredOnline--
+
+	n.mu.Lock()
+	delete(n.participationRates, name)
+	n.updateMinParticipationRate()
+	n.mu.Unlock()
 }
 
 func (n *Network) updateHealth() {
@@ -197,3 +302,30 @@ func (n *Network) Uptime() float64 {
 func (n *Network) StartTime() time.Time {
 	return n.UptimeData.StartTime
 }
+
+// Copy returns a copy of the network statistics, safe to read or serialize
+// while the network keeps being updated. Only exported fields are copied.
+func (n *Network) Copy() *Network {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	uptimeData := *n.UptimeData
+	evidence := make([]*DoubleSignEvidence, len(n.Evidence))
+	copy(evidence, n.Evidence)
+
+	return &Network{
+		Height:                  n.Height,
+		Round:                   n.Round,
+		SlowConsensus:           n.SlowConsensus,
+		AvgBlockTime:            n.AvgBlockTime,
+		AvgTxThroughput:         n.AvgTxThroughput,
+		AvgBlockLatency:         n.AvgBlockLatency,
+		NumValidators:           n.NumValidators,
+		NumNodesMonitored:       n.NumNodesMonitored,
+		NumNodesMonitoredOnline: n.NumNodesMonitoredOnline,
+		Health:                  n.Health,
+		MinParticipationRate:    n.MinParticipationRate,
+		UptimeData:              &uptimeData,
+		Evidence:                evidence,
+	}
+}
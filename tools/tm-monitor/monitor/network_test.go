@@ -19,6 +19,64 @@ func TestNetworkNewBlock(t *testing.T) {
 	assert.Equal(t, 0.0, n.AvgTxThroughput)
 }
 
+func TestNetworkSlowConsensus(t *testing.T) {
+	n := monitor.NewNetwork()
+	n.NewBlock(tmtypes.Header{Height: 5})
+
+	assert.False(t, n.NewRound(6, 0))
+	assert.False(t, n.NewRound(6, 3))
+	assert.Equal(t, 3, n.Round)
+	assert.False(t, n.SlowConsensus)
+
+	assert.True(t, n.NewRound(6, 4))
+	assert.True(t, n.SlowConsensus)
+	// only reported once per height
+	assert.False(t, n.NewRound(6, 5))
+	assert.True(t, n.SlowConsensus)
+	// stale rounds are ignored
+	assert.False(t, n.NewRound(5, 7))
+	assert.Equal(t, 5, n.Round)
+
+	n.NewBlock(tmtypes.Header{Height: 6})
+	assert.False(t, n.SlowConsensus)
+	assert.Equal(t, 0, n.Round)
+}
+
+func TestNetworkSlowRoundThreshold(t *testing.T) {
+	n := monitor.NewNetwork()
+	n.SetSlowRoundThreshold(1)
+
+	assert.False(t, n.NewRound(1, 1))
+	assert.True(t, n.NewRound(1, 2))
+	assert.True(t, n.SlowConsensus)
+
+	// a new height clears the flag even before the block is seen
+	assert.False(t, n.NewRound(2, 0))
+	assert.False(t, n.SlowConsensus)
+}
+
+func TestNetworkParticipationRate(t *testing.T) {
+	n := monitor.NewNetwork()
+	n.SetParticipationThreshold(0.5)
+	assert.Equal(t, 1.0, n.MinParticipationRate)
+
+	assert.False(t, n.NewParticipationRate("a", 0.9))
+	assert.False(t, n.NewParticipationRate("b", 0.6))
+	assert.Equal(t, 0.6, n.MinParticipationRate)
+
+	// reported once when crossing the threshold
+	assert.True(t, n.NewParticipationRate("b", 0.4))
+	assert.False(t, n.NewParticipationRate("b", 0.3))
+	assert.Equal(t, 0.3, n.MinParticipationRate)
+
+	// and again after recovering
+	assert.False(t, n.NewParticipationRate("b", 0.7))
+	assert.True(t, n.NewParticipationRate("b", 0.2))
+
+	n.NodeDeleted("b")
+	assert.Equal(t, 0.9, n.MinParticipationRate)
+}
+
 func TestNetworkNewBlockLatency(t *testing.T) {
 	n := monitor.NewNetwork()
 
@@ -1,13 +1,16 @@
 package monitor
 
 import (
+	"bytes"
 	"encoding/json"
 	"math"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
 
 	crypto "github.com/tendermint/tendermint/crypto"
+	cmn "github.com/tendermint/tendermint/libs/common"
 	"github.com/tendermint/tendermint/libs/events"
 	"github.com/tendermint/tendermint/libs/log"
 	ctypes "github.com/tendermint/tendermint/rpc/core/types"
@@ -21,23 +24,47 @@ const maxRestarts = 25
 type Node struct {
 	rpcAddr string
 
+	// mtx guards the fields below, which are updated from the event meter
+	// callbacks and the status polling routine.
+	mtx sync.Mutex
+
 	IsValidator bool          `json:"is_validator"` // validator or non-validator?
 	pubKey      crypto.PubKey `json:"pub_key"`
 
 	Name         string  `json:"name"`
 	Online       bool    `json:"online"`
 	Height       int64   `json:"height"`
+	Round        int     `json:"round"`
 	BlockLatency float64 `json:"block_latency" amino:"unsafe"` // ms, interval between block commits
 
+	// Latest block as reported by the node's status; polled, so it's
+	// available even when no events are coming in.
+	LatestBlockHash cmn.HexBytes `json:"latest_block_hash"`
+	LatestBlockTime time.Time    `json:"latest_block_time"`
+	CatchingUp      bool         `json:"catching_up"`
+
+	// LatencyStats is the full ping/pong latency distribution (ns).
+	LatencyStats em.LatencySnapshot `json:"latency_stats"`
+
+	// ParticipationRate is the share of recent blocks whose commit includes
+	// this node's precommit. Only tracked for validators.
+	ParticipationRate   float64 `json:"participation_rate" amino:"unsafe"`
+	participationWindow int
+	signed              []bool // ring buffer of the last participationWindow blocks
+	signedIdx           int
+
 	// em holds the ws connection. Each eventMeter callback is called in a separate go-routine.
 	em eventMeter
 
 	// rpcClient is an client for making RPC calls to TM
 	rpcClient rpc_client.HTTPClient
 
-	blockCh        chan<- tmtypes.Header
-	blockLatencyCh chan<- float64
-	disconnectCh   chan<- bool
+	blockCh         chan<- tmtypes.Header
+	blockLatencyCh  chan<- float64
+	disconnectCh    chan<- bool
+	roundCh         chan<- tmtypes.EventDataRoundState
+	voteCh          chan<- *tmtypes.Vote
+	participationCh chan<- float64
 
 	checkIsValidatorInterval time.Duration
 
@@ -61,6 +88,7 @@ func NewNodeWithEventMeterAndRpcClient(rpcAddr string, em eventMeter, rpcClient
 		Name:      rpcAddr,
 		quit:      make(chan struct{}),
 		checkIsValidatorInterval: 5 * time.Second,
+		participationWindow:      100,
 		logger: log.NewNopLogger(),
 	}
 
@@ -79,6 +107,14 @@ func SetCheckIsValidatorInterval(d time.Duration) func(n *Node) {
 	}
 }
 
+// SetParticipationWindow lets you change the number of recent blocks the
+// participation rate is calculated over. A window below 1 disables tracking.
+func SetParticipationWindow(blocks int) func(n *Node) {
+	return func(n *Node) {
+		n.participationWindow = blocks
+	}
+}
+
 func (n *Node) SendBlocksTo(ch chan<- tmtypes.Header) {
 	n.blockCh = ch
 }
@@ -91,6 +127,18 @@ func (n *Node) NotifyAboutDisconnects(ch chan<- bool) {
 	n.disconnectCh = ch
 }
 
+func (n *Node) SendRoundsTo(ch chan<- tmtypes.EventDataRoundState) {
+	n.roundCh = ch
+}
+
+func (n *Node) SendVotesTo(ch chan<- *tmtypes.Vote) {
+	n.voteCh = ch
+}
+
+func (n *Node) SendParticipationRatesTo(ch chan<- float64) {
+	n.participationCh = ch
+}
+
 // SetLogger lets you set your own logger
 func (n *Node) SetLogger(l log.Logger) {
 	n.logger = l
@@ -103,14 +151,26 @@ func (n *Node) Start() error {
 	}
 
 	n.em.RegisterLatencyCallback(latencyCallback(n))
-	err := n.em.Subscribe(tmtypes.EventQueryNewBlockHeader.String(), newBlockCallback(n))
+	err := n.em.Subscribe(tmtypes.EventQueryNewBlock.String(), newBlockCallback(n))
+	if err != nil {
+		return err
+	}
+	err = n.em.Subscribe(tmtypes.EventQueryNewRoundStep.String(), newRoundStepCallback(n))
+	if err != nil {
+		return err
+	}
+	err = n.em.Subscribe(tmtypes.EventQueryVote.String(), newVoteCallback(n))
 	if err != nil {
 		return err
 	}
 	n.em.RegisterDisconnectCallback(disconnectCallback(n))
+	n.em.RegisterReconnectCallback(reconnectCallback(n))
 
+	n.mtx.Lock()
 	n.Online = true
+	n.mtx.Unlock()
 
+	n.checkStatus()
 	n.checkIsValidator()
 	go n.checkIsValidatorLoop()
 
@@ -118,7 +178,9 @@ func (n *Node) Start() error {
 }
 
 func (n *Node) Stop() {
+	n.mtx.Lock()
 	n.Online = false
+	n.mtx.Unlock()
 
 	n.em.Stop()
 
@@ -128,25 +190,98 @@ func (n *Node) Stop() {
 // implements eventmeter.EventCallbackFunc
 func newBlockCallback(n *Node) em.EventCallbackFunc {
 	return func(metric *em.EventMetric, data interface{}) {
-		block := data.(tmtypes.TMEventData).(tmtypes.EventDataNewBlockHeader).Header
+		block := data.(tmtypes.TMEventData).(tmtypes.EventDataNewBlock).Block
 
+		n.mtx.Lock()
 		n.Height = block.Height
+		isValidator := n.IsValidator
+		n.mtx.Unlock()
 		n.logger.Info("new block", "height", block.Height, "numTxs", block.NumTxs)
 
 		if n.blockCh != nil {
-			n.blockCh <- block
+			n.blockCh <- block.Header
+		}
+
+		// the first block has no last commit
+		if isValidator && n.participationWindow > 0 && block.Height > 1 && block.LastCommit != nil {
+			rate := n.recordParticipation(block.LastCommit)
+			if n.participationCh != nil {
+				n.participationCh <- rate
+			}
+		}
+	}
+}
+
+// recordParticipation checks whether the node signed the given commit and
+// updates and returns the participation rate.
+func (n *Node) recordParticipation(commit *tmtypes.Commit) float64 {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	addr := n.pubKey.Address()
+	signed := false
+	for _, vote := range commit.Precommits {
+		if vote != nil && bytes.Equal(vote.ValidatorAddress, addr) {
+			signed = true
+			break
+		}
+	}
+
+	if len(n.signed) < n.participationWindow {
+		n.signed = append(n.signed, signed)
+	} else {
+		n.signed[n.signedIdx] = signed
+		n.signedIdx = (n.signedIdx + 1) % n.participationWindow
+	}
+
+	numSigned := 0
+	for _, s := range n.signed {
+		if s {
+			numSigned++
+		}
+	}
+	n.ParticipationRate = float64(numSigned) / float64(len(n.signed))
+	return n.ParticipationRate
+}
+
+// implements eventmeter.EventCallbackFunc
+func newRoundStepCallback(n *Node) em.EventCallbackFunc {
+	return func(metric *em.EventMetric, data interface{}) {
+		rs := data.(tmtypes.TMEventData).(tmtypes.EventDataRoundState)
+
+		n.mtx.Lock()
+		n.Round = rs.Round
+		n.mtx.Unlock()
+		n.logger.Debug("new round step", "height", rs.Height, "round", rs.Round, "step", rs.Step)
+
+		if n.roundCh != nil {
+			n.roundCh <- rs
+		}
+	}
+}
+
+// implements eventmeter.EventCallbackFunc
+func newVoteCallback(n *Node) em.EventCallbackFunc {
+	return func(metric *em.EventMetric, data interface{}) {
+		vote := data.(tmtypes.TMEventData).(tmtypes.EventDataVote).Vote
+
+		if n.voteCh != nil {
+			n.voteCh <- vote
 		}
 	}
 }
 
 // implements eventmeter.EventLatencyFunc
 func latencyCallback(n *Node) em.LatencyCallbackFunc {
-	return func(latency float64) {
-		n.BlockLatency = latency / 1000000.0 // ns to ms
-		n.logger.Info("new block latency", "latency", n.BlockLatency)
+	return func(latency em.LatencySnapshot) {
+		n.mtx.Lock()
+		n.LatencyStats = latency
+		n.BlockLatency = latency.Mean / 1000000.0 // ns to ms
+		n.mtx.Unlock()
+		n.logger.Info("new block latency", "latency", latency.Mean/1000000.0, "p99", latency.P99/1000000.0)
 
 		if n.blockLatencyCh != nil {
-			n.blockLatencyCh <- latency
+			n.blockLatencyCh <- latency.Mean
 		}
 	}
 }
@@ -154,7 +289,7 @@ func latencyCallback(n *Node) em.LatencyCallbackFunc {
 // implements eventmeter.DisconnectCallbackFunc
 func disconnectCallback(n *Node) em.DisconnectCallbackFunc {
 	return func() {
-		n.Online = false
+		n.setOnline(false)
 		n.logger.Info("status", "down")
 
 		if n.disconnectCh != nil {
@@ -163,6 +298,18 @@ func disconnectCallback(n *Node) em.DisconnectCallbackFunc {
 	}
 }
 
+// implements eventmeter.ReconnectCallbackFunc
+func reconnectCallback(n *Node) em.ReconnectCallbackFunc {
+	return func() {
+		n.setOnline(true)
+		n.logger.Info("status", "up")
+
+		if n.disconnectCh != nil {
+			n.disconnectCh <- false
+		}
+	}
+}
+
 func (n *Node) RestartEventMeterBackoff() error {
 	attempt := 0
 
@@ -207,6 +354,7 @@ func (n *Node) checkIsValidatorLoop() {
 		case <-n.quit:
 			return
 		case <-time.After(n.checkIsValidatorInterval):
+			n.checkStatus()
 			n.checkIsValidator()
 		}
 	}
@@ -219,7 +367,9 @@ func (n *Node) checkIsValidator() {
 			key, err1 := n.getPubKey()
 			// TODO: use bytes.Equal
 			if err1 == nil && v.PubKey == key {
+				n.mtx.Lock()
 				n.IsValidator = true
+				n.mtx.Unlock()
 			}
 		}
 	} else {
@@ -227,9 +377,31 @@ func (n *Node) checkIsValidator() {
 	}
 }
 
+// checkStatus records the node's latest block and whether it is catching up.
+func (n *Node) checkStatus() {
+	status := new(ctypes.ResultStatus)
+	if _, err := n.rpcClient.Call("status", nil, status); err != nil {
+		n.logger.Info("check status failed", "err", err)
+		return
+	}
+
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	n.pubKey = status.ValidatorInfo.PubKey
+	if status.SyncInfo.LatestBlockHeight > n.Height {
+		n.Height = status.SyncInfo.LatestBlockHeight
+	}
+	n.LatestBlockHash = status.SyncInfo.LatestBlockHash
+	n.LatestBlockTime = status.SyncInfo.LatestBlockTime
+	n.CatchingUp = status.SyncInfo.CatchingUp
+}
+
 func (n *Node) getPubKey() (crypto.PubKey, error) {
-	if n.pubKey != nil {
-		return n.pubKey, nil
+	n.mtx.Lock()
+	pubKey := n.pubKey
+	n.mtx.Unlock()
+	if pubKey != nil {
+		return pubKey, nil
 	}
 
 	status := new(ctypes.ResultStatus)
@@ -237,15 +409,45 @@ func (n *Node) getPubKey() (crypto.PubKey, error) {
 	if err != nil {
 		return nil, err
 	}
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
 	n.pubKey = status.ValidatorInfo.PubKey
 	return n.pubKey, nil
 }
 
+func (n *Node) setOnline(online bool) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	n.Online = online
+}
+
+// Copy returns a copy of the node's statistics, safe to read or serialize
+// while the node keeps being updated. Only exported fields are copied.
+func (n *Node) Copy() *Node {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	return &Node{
+		IsValidator:       n.IsValidator,
+		Name:              n.Name,
+		Online:            n.Online,
+		Height:            n.Height,
+		Round:             n.Round,
+		BlockLatency:      n.BlockLatency,
+		LatestBlockHash:   n.LatestBlockHash,
+		LatestBlockTime:   n.LatestBlockTime,
+		CatchingUp:        n.CatchingUp,
+		LatencyStats:      n.LatencyStats,
+		ParticipationRate: n.ParticipationRate,
+	}
+}
+
 type eventMeter interface {
 	Start() error
 	Stop()
 	RegisterLatencyCallback(em.LatencyCallbackFunc)
 	RegisterDisconnectCallback(em.DisconnectCallbackFunc)
+	RegisterReconnectCallback(em.ReconnectCallbackFunc)
 	Subscribe(string, em.EventCallbackFunc) error
 	Unsubscribe(string) error
 	SetLogger(l log.Logger)
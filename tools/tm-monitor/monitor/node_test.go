@@ -7,7 +7,9 @@ import (
 	"github.com/stretchr/testify/require"
 
 	amino "github.com/tendermint/go-amino"
+	"github.com/tendermint/tendermint/crypto"
 	"github.com/tendermint/tendermint/crypto/ed25519"
+	cmn "github.com/tendermint/tendermint/libs/common"
 	ctypes "github.com/tendermint/tendermint/rpc/core/types"
 	em "github.com/tendermint/tendermint/tools/tm-monitor/eventmeter"
 	mock "github.com/tendermint/tendermint/tools/tm-monitor/mock"
@@ -16,7 +18,8 @@ import (
 )
 
 const (
-	blockHeight = int64(1)
+	blockHeight       = int64(1)
+	latestBlockHeight = int64(10)
 )
 
 func TestNodeStartStop(t *testing.T) {
@@ -27,6 +30,15 @@ func TestNodeStartStop(t *testing.T) {
 	assert.Equal(t, true, n.IsValidator)
 }
 
+func TestNodeStatusPolled(t *testing.T) {
+	n, _ := startValidatorNode(t)
+	defer n.Stop()
+
+	assert.Equal(t, latestBlockHeight, n.Height)
+	assert.Equal(t, cmn.HexBytes("hash"), n.LatestBlockHash)
+	assert.Equal(t, true, n.CatchingUp)
+}
+
 func TestNodeNewBlockReceived(t *testing.T) {
 	blockCh := make(chan tmtypes.Header, 100)
 	n, emMock := startValidatorNode(t)
@@ -34,21 +46,88 @@ func TestNodeNewBlockReceived(t *testing.T) {
 	n.SendBlocksTo(blockCh)
 
 	blockHeader := tmtypes.Header{Height: 5}
-	emMock.Call("eventCallback", &em.EventMetric{}, tmtypes.EventDataNewBlockHeader{blockHeader})
+	emMock.Call("eventCallback", &em.EventMetric{}, tmtypes.EventDataNewBlock{Block: &tmtypes.Block{Header: blockHeader}})
 
 	assert.Equal(t, int64(5), n.Height)
 	assert.Equal(t, blockHeader, <-blockCh)
 }
 
+func TestNodeNewRoundStepReceived(t *testing.T) {
+	roundCh := make(chan tmtypes.EventDataRoundState, 100)
+	n, emMock := startValidatorNode(t)
+	defer n.Stop()
+	n.SendRoundsTo(roundCh)
+
+	rs := tmtypes.EventDataRoundState{Height: 5, Round: 2, Step: "RoundStepPropose"}
+	emMock.Call("eventCallback", &em.EventMetric{}, rs)
+
+	assert.Equal(t, 2, n.Round)
+	assert.Equal(t, rs, <-roundCh)
+}
+
+func TestNodeNewVoteReceived(t *testing.T) {
+	voteCh := make(chan *tmtypes.Vote, 100)
+	n, emMock := startValidatorNode(t)
+	defer n.Stop()
+	n.SendVotesTo(voteCh)
+
+	vote := &tmtypes.Vote{Height: 5, Type: tmtypes.VoteTypePrevote}
+	emMock.Call("eventCallback", &em.EventMetric{}, tmtypes.EventDataVote{Vote: vote})
+
+	assert.Equal(t, vote, <-voteCh)
+}
+
+func TestNodeParticipationRate(t *testing.T) {
+	participationCh := make(chan float64, 100)
+	pubKey := ed25519.GenPrivKey().PubKey()
+	n, emMock := startValidatorNodeWithPubKey(t, pubKey, monitor.SetParticipationWindow(6))
+	defer n.Stop()
+	n.SendParticipationRatesTo(participationCh)
+
+	ourVote := &tmtypes.Vote{ValidatorAddress: pubKey.Address()}
+	otherVote := &tmtypes.Vote{ValidatorAddress: []byte("other")}
+	for h := int64(2); h <= 10; h++ {
+		// miss every third block
+		precommits := []*tmtypes.Vote{otherVote, ourVote}
+		if h%3 == 0 {
+			precommits = []*tmtypes.Vote{otherVote, nil}
+		}
+		block := &tmtypes.Block{Header: tmtypes.Header{Height: h}, LastCommit: &tmtypes.Commit{Precommits: precommits}}
+		emMock.Call("eventCallback", &em.EventMetric{}, tmtypes.EventDataNewBlock{Block: block})
+	}
+
+	// the last 6 blocks (5-10) miss 6 and 9
+	assert.InDelta(t, 4.0/6.0, n.ParticipationRate, 0.0001)
+	assert.Len(t, participationCh, 9)
+	assert.Equal(t, 1.0, <-participationCh)
+}
+
+func TestNodeParticipationRateDisabled(t *testing.T) {
+	participationCh := make(chan float64, 100)
+	pubKey := ed25519.GenPrivKey().PubKey()
+	n, emMock := startValidatorNodeWithPubKey(t, pubKey, monitor.SetParticipationWindow(0))
+	defer n.Stop()
+	n.SendParticipationRatesTo(participationCh)
+
+	ourVote := &tmtypes.Vote{ValidatorAddress: pubKey.Address()}
+	block := &tmtypes.Block{Header: tmtypes.Header{Height: 2}, LastCommit: &tmtypes.Commit{Precommits: []*tmtypes.Vote{ourVote}}}
+	emMock.Call("eventCallback", &em.EventMetric{}, tmtypes.EventDataNewBlock{Block: block})
+
+	assert.Equal(t, 0.0, n.ParticipationRate)
+	assert.Empty(t, participationCh)
+}
+
 func TestNodeNewBlockLatencyReceived(t *testing.T) {
 	blockLatencyCh := make(chan float64, 100)
 	n, emMock := startValidatorNode(t)
 	defer n.Stop()
 	n.SendBlockLatenciesTo(blockLatencyCh)
 
-	emMock.Call("latencyCallback", 1000000.0)
+	latency := em.LatencySnapshot{Mean: 1000000.0, Min: 500000, Max: 3000000, P99: 2900000.0}
+	emMock.Call("latencyCallback", latency)
 
 	assert.Equal(t, 1.0, n.BlockLatency)
+	assert.Equal(t, latency, n.LatencyStats)
 	assert.Equal(t, 1000000.0, <-blockLatencyCh)
 }
 
@@ -64,6 +143,20 @@ func TestNodeConnectionLost(t *testing.T) {
 	assert.Equal(t, false, n.Online)
 }
 
+func TestNodeConnectionRestored(t *testing.T) {
+	disconnectCh := make(chan bool, 100)
+	n, emMock := startValidatorNode(t)
+	defer n.Stop()
+	n.NotifyAboutDisconnects(disconnectCh)
+
+	emMock.Call("disconnectCallback")
+	assert.Equal(t, true, <-disconnectCh)
+
+	emMock.Call("reconnectCallback")
+	assert.Equal(t, false, <-disconnectCh)
+	assert.Equal(t, true, n.Online)
+}
+
 func TestNumValidators(t *testing.T) {
 	n, _ := startValidatorNode(t)
 	defer n.Stop()
@@ -75,17 +168,23 @@ func TestNumValidators(t *testing.T) {
 }
 
 func startValidatorNode(t *testing.T) (n *monitor.Node, emMock *mock.EventMeter) {
+	return startValidatorNodeWithPubKey(t, ed25519.GenPrivKey().PubKey())
+}
+
+func startValidatorNodeWithPubKey(t *testing.T, pubKey crypto.PubKey, options ...func(*monitor.Node)) (n *monitor.Node, emMock *mock.EventMeter) {
 	emMock = &mock.EventMeter{}
 
 	stubs := make(map[string]interface{})
-	pubKey := ed25519.GenPrivKey().PubKey()
 	stubs["validators"] = ctypes.ResultValidators{BlockHeight: blockHeight, Validators: []*tmtypes.Validator{tmtypes.NewValidator(pubKey, 0)}}
-	stubs["status"] = ctypes.ResultStatus{ValidatorInfo: ctypes.ValidatorInfo{PubKey: pubKey}}
+	stubs["status"] = ctypes.ResultStatus{
+		SyncInfo:      ctypes.SyncInfo{LatestBlockHash: cmn.HexBytes("hash"), LatestBlockHeight: latestBlockHeight, CatchingUp: true},
+		ValidatorInfo: ctypes.ValidatorInfo{PubKey: pubKey},
+	}
 	cdc := amino.NewCodec()
 	rpcClientMock := &mock.RpcClient{Stubs: stubs}
 	rpcClientMock.SetCodec(cdc)
 
-	n = monitor.NewNodeWithEventMeterAndRpcClient("tcp://127.0.0.1:26657", emMock, rpcClientMock)
+	n = monitor.NewNodeWithEventMeterAndRpcClient("tcp://127.0.0.1:26657", emMock, rpcClientMock, options...)
 
 	err := n.Start()
 	require.Nil(t, err)
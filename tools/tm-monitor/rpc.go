@@ -28,6 +28,8 @@ func routes(m *monitor.Monitor) map[string]*rpc.RPCFunc {
 		"status/node":    rpc.NewRPCFunc(RPCNodeStatus(m), "name"),
 		"monitor":        rpc.NewRPCFunc(RPCMonitor(m), "endpoint"),
 		"unmonitor":      rpc.NewRPCFunc(RPCUnmonitor(m), "endpoint"),
+		"evidence":       rpc.NewRPCFunc(RPCEvidence(m), ""),
+		"dump":           rpc.NewRPCFunc(RPCDump(m), "generation"),
 
 		// "start_meter": rpc.NewRPCFunc(network.StartMeter, "chainID,valID,event"),
 		// "stop_meter":  rpc.NewRPCFunc(network.StopMeter, "chainID,valID,event"),
@@ -53,7 +55,7 @@ func RPCNetworkStatus(m *monitor.Monitor) interface{} {
 func RPCNodeStatus(m *monitor.Monitor) interface{} {
 	return func(name string) (*monitor.Node, error) {
 		if i, n := m.NodeByName(name); i != -1 {
-			return n, nil
+			return n.Copy(), nil
 		}
 		return nil, errors.New("Cannot find node with that name")
 	}
@@ -116,6 +118,22 @@ func RPCUnmonitor(m *monitor.Monitor) interface{} {
 // 	return val.EventMeter().GetMetric(eventID)
 // }
 
+// RPCEvidence returns the double signing incidents detected so far.
+func RPCEvidence(m *monitor.Monitor) interface{} {
+	return func() ([]*monitor.DoubleSignEvidence, error) {
+		return m.Network.GetEvidence(), nil
+	}
+}
+
+// RPCDump returns the network and every node's statistics in one response.
+// Pass the generation of the previous dump to only get a "not modified"
+// result if nothing has changed since.
+func RPCDump(m *monitor.Monitor) interface{} {
+	return func(generation int64) (*monitor.Snapshot, error) {
+		return m.Snapshot(generation)
+	}
+}
+
 //--> types
 
 type networkAndNodes struct {
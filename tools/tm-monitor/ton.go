@@ -60,20 +60,25 @@ func (o *Ton) Stop() {
 }
 
 func (o *Ton) printHeader() {
-	n := o.monitor.Network
+	n := o.monitor.Network.Copy()
 	fmt.Fprintf(o.Output, "%v up %.2f%%\n", n.StartTime(), n.Uptime())
 	fmt.Println()
 	fmt.Fprintf(o.Output, "Height: %d\n", n.Height)
+	if n.SlowConsensus {
+		fmt.Fprintf(o.Output, "Consensus is slow (round: %d)\n", n.Round)
+	}
 	fmt.Fprintf(o.Output, "Avg block time: %.3f ms\n", n.AvgBlockTime)
 	fmt.Fprintf(o.Output, "Avg tx throughput: %.0f per sec\n", n.AvgTxThroughput)
 	fmt.Fprintf(o.Output, "Avg block latency: %.3f ms\n", n.AvgBlockLatency)
 	fmt.Fprintf(o.Output, "Active nodes: %d/%d (health: %s) Validators: %d\n", n.NumNodesMonitoredOnline, n.NumNodesMonitored, n.GetHealthString(), n.NumValidators)
+	fmt.Fprintf(o.Output, "Min participation: %.0f%%\n", n.MinParticipationRate*100)
 }
 
 func (o *Ton) printTable() {
 	w := tabwriter.NewWriter(o.Output, 0, 0, 5, ' ', 0)
 	fmt.Fprintln(w, "NAME\tHEIGHT\tBLOCK LATENCY\tONLINE\tVALIDATOR\t")
 	for _, n := range o.monitor.Nodes {
+		n = n.Copy()
 		fmt.Fprintln(w, fmt.Sprintf("%s\t%d\t%.3f ms\t%v\t%v\t", n.Name, n.Height, n.BlockLatency, n.Online, n.IsValidator))
 	}
 	w.Flush()
@@ -11,6 +11,7 @@ import (
 	"github.com/tendermint/tendermint/crypto/merkle"
 	"github.com/tendermint/tendermint/crypto/tmhash"
 	cmn "github.com/tendermint/tendermint/libs/common"
+	tmtime "github.com/tendermint/tendermint/types/time"
 )
 
 // Block defines the atomic unit of a Tendermint blockchain.
@@ -29,7 +30,7 @@ func MakeBlock(height int64, txs []Tx, commit *Commit, evidence []Evidence) *Blo
 	block := &Block{
 		Header: Header{
 			Height: height,
-			Time:   time.Now(),
+			Time:   tmtime.Now(),
 			NumTxs: int64(len(txs)),
 		},
 		Data: Data{
@@ -51,6 +52,9 @@ func (b *Block) ValidateBasic() error {
 	b.mtx.Lock()
 	defer b.mtx.Unlock()
 
+	if err := b.Header.ValidateBasic(); err != nil {
+		return err
+	}
 	newTxs := int64(len(b.Data.Txs))
 	if b.NumTxs != newTxs {
 		return fmt.Errorf("Wrong Block.Header.NumTxs. Expected %v, got %v", newTxs, b.NumTxs)
@@ -202,6 +206,15 @@ type Header struct {
 	EvidenceHash cmn.HexBytes `json:"evidence_hash"` // evidence included in the block
 }
 
+// ValidateBasic performs basic validation that doesn't involve state data.
+// It checks that the header time is in range.
+func (h *Header) ValidateBasic() error {
+	if err := tmtime.Validate(h.Time); err != nil {
+		return fmt.Errorf("Wrong Header.Time: %v", err)
+	}
+	return nil
+}
+
 // Hash returns the hash of the header.
 // Returns nil if ValidatorHash is missing,
 // since a Header is not valid unless there is
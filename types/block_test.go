@@ -2,12 +2,14 @@ package types
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/tendermint/tendermint/crypto"
 	cmn "github.com/tendermint/tendermint/libs/common"
+	tmtime "github.com/tendermint/tendermint/types/time"
 )
 
 func TestBlockAddEvidence(t *testing.T) {
@@ -86,6 +88,18 @@ func TestBlockValidateBasic(t *testing.T) {
 	block.EvidenceHash = []byte("something else")
 	err = block.ValidateBasic()
 	require.Error(t, err)
+
+	// time before the epoch
+	block = MakeBlock(h, txs, commit, evList)
+	block.Time = tmtime.MinTime.Add(-time.Second)
+	err = block.ValidateBasic()
+	require.Error(t, err)
+
+	// time in the far future
+	block = MakeBlock(h, txs, commit, evList)
+	block.Time = tmtime.MaxTime.Add(time.Second)
+	err = block.ValidateBasic()
+	require.Error(t, err)
 }
 
 func TestBlockHash(t *testing.T) {
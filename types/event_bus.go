@@ -118,6 +118,20 @@ func (b *EventBus) PublishEventTx(event EventDataTx) error {
 	return nil
 }
 
+// PublishEventExpiredTx publishes an expired tx event, tagged with the
+// tx hash (TxHashKey).
+func (b *EventBus) PublishEventExpiredTx(event EventDataExpiredTx) error {
+	// no explicit deadline for publishing events
+	ctx := context.Background()
+
+	tags := map[string]string{
+		EventTypeKey: EventExpiredTx,
+		TxHashKey:    fmt.Sprintf("%X", event.Tx.Hash()),
+	}
+	b.pubsub.PublishWithTags(ctx, event, tmpubsub.NewTagMap(tags))
+	return nil
+}
+
 func (b *EventBus) PublishEventProposalHeartbeat(event EventDataProposalHeartbeat) error {
 	return b.Publish(EventProposalHeartbeat, event)
 }
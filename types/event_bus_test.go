@@ -58,6 +58,29 @@ func TestEventBusPublishEventTx(t *testing.T) {
 	}
 }
 
+func TestEventBusPublishEventExpiredTx(t *testing.T) {
+	eventBus := NewEventBus()
+	err := eventBus.Start()
+	require.NoError(t, err)
+	defer eventBus.Stop()
+
+	tx := Tx("foo")
+	txEventsCh := make(chan interface{}, 1)
+	query := fmt.Sprintf("tm.event='ExpiredTx' AND tx.hash='%X'", tx.Hash())
+	err = eventBus.Subscribe(context.Background(), "test", tmquery.MustParse(query), txEventsCh)
+	require.NoError(t, err)
+
+	err = eventBus.PublishEventExpiredTx(EventDataExpiredTx{Tx: tx})
+	assert.NoError(t, err)
+
+	select {
+	case e := <-txEventsCh:
+		assert.Equal(t, tx, e.(EventDataExpiredTx).Tx)
+	case <-time.After(1 * time.Second):
+		t.Fatal("did not receive an expired transaction after 1 sec.")
+	}
+}
+
 func TestEventBusPublish(t *testing.T) {
 	eventBus := NewEventBus()
 	err := eventBus.Start()
@@ -11,6 +11,7 @@ import (
 // Reserved event types
 const (
 	EventCompleteProposal  = "CompleteProposal"
+	EventExpiredTx         = "ExpiredTx"
 	EventLock              = "Lock"
 	EventNewBlock          = "NewBlock"
 	EventNewBlockHeader    = "NewBlockHeader"
@@ -39,6 +40,7 @@ type TMEventData interface {
 func (_ EventDataNewBlock) AssertIsTMEventData()          {}
 func (_ EventDataNewBlockHeader) AssertIsTMEventData()    {}
 func (_ EventDataTx) AssertIsTMEventData()                {}
+func (_ EventDataExpiredTx) AssertIsTMEventData()         {}
 func (_ EventDataRoundState) AssertIsTMEventData()        {}
 func (_ EventDataVote) AssertIsTMEventData()              {}
 func (_ EventDataProposalHeartbeat) AssertIsTMEventData() {}
@@ -49,6 +51,7 @@ func RegisterEventDatas(cdc *amino.Codec) {
 	cdc.RegisterConcrete(EventDataNewBlock{}, "tendermint/event/NewBlock", nil)
 	cdc.RegisterConcrete(EventDataNewBlockHeader{}, "tendermint/event/NewBlockHeader", nil)
 	cdc.RegisterConcrete(EventDataTx{}, "tendermint/event/Tx", nil)
+	cdc.RegisterConcrete(EventDataExpiredTx{}, "tendermint/event/ExpiredTx", nil)
 	cdc.RegisterConcrete(EventDataRoundState{}, "tendermint/event/RoundState", nil)
 	cdc.RegisterConcrete(EventDataVote{}, "tendermint/event/Vote", nil)
 	cdc.RegisterConcrete(EventDataProposalHeartbeat{}, "tendermint/event/ProposalHeartbeat", nil)
@@ -72,6 +75,12 @@ type EventDataTx struct {
 	TxResult
 }
 
+// EventDataExpiredTx is fired for txs dropped from the mempool
+// because they outlived its TTL.
+type EventDataExpiredTx struct {
+	Tx Tx `json:"tx"`
+}
+
 type EventDataProposalHeartbeat struct {
 	Heartbeat *Heartbeat
 }
@@ -122,6 +131,7 @@ var (
 	EventQueryVote              = QueryForEvent(EventVote)
 	EventQueryProposalHeartbeat = QueryForEvent(EventProposalHeartbeat)
 	EventQueryTx                = QueryForEvent(EventTx)
+	EventQueryExpiredTx         = QueryForEvent(EventExpiredTx)
 )
 
 func EventQueryTxFor(tx Tx) tmpubsub.Query {
@@ -7,6 +7,7 @@ import (
 
 	"github.com/tendermint/tendermint/crypto"
 	cmn "github.com/tendermint/tendermint/libs/common"
+	tmtime "github.com/tendermint/tendermint/types/time"
 )
 
 //------------------------------------------------------------
@@ -75,8 +76,11 @@ func (genDoc *GenesisDoc) ValidateAndComplete() error {
 	}
 
 	if genDoc.GenesisTime.IsZero() {
-		genDoc.GenesisTime = time.Now()
+		genDoc.GenesisTime = tmtime.Now()
 	}
+	// amino only decodes UTC times, but a doc built in code may carry any
+	// location and a monotonic reading
+	genDoc.GenesisTime = tmtime.Canonical(genDoc.GenesisTime)
 
 	return nil
 }
@@ -64,6 +64,22 @@ func TestGenesisGood(t *testing.T) {
 	assert.Error(t, err, "expected error for genDoc json with block size of 0")
 }
 
+func TestGenesisTimeUTC(t *testing.T) {
+	utc := time.Date(2018, 2, 11, 7, 9, 22, 765000000, time.UTC)
+	genDoc := &GenesisDoc{
+		ChainID:     "abc",
+		GenesisTime: utc.In(time.FixedZone("UTC+2", 2*60*60)),
+		Validators:  []GenesisValidator{{ed25519.GenPrivKey().PubKey(), 10, "myval"}},
+	}
+	require.NoError(t, genDoc.ValidateAndComplete())
+	assert.Equal(t, utc, genDoc.GenesisTime)
+
+	// amino refuses times that aren't UTC
+	genDocBytes := []byte(`{"genesis_time":"2018-02-11T09:09:22.765+02:00","chain_id":"test-chain-QDKdJr","validators":[{"pub_key":{"type":"tendermint/PubKeyEd25519","value":"AT/+aaL1eB0477Mud9JMm8Sh8BIvOYlPGC9KkIUmFaE="},"power":"10","name":""}]}`)
+	_, err := GenesisDocFromJSON(genDocBytes)
+	assert.Error(t, err)
+}
+
 func TestGenesisSaveAs(t *testing.T) {
 	tmpfile, err := ioutil.TempFile("", "genesis")
 	require.NoError(t, err)
@@ -38,6 +38,10 @@ func (NopEventBus) PublishEventTx(tx EventDataTx) error {
 	return nil
 }
 
+func (NopEventBus) PublishEventExpiredTx(tx EventDataExpiredTx) error {
+	return nil
+}
+
 //--- EventDataRoundState events
 
 func (NopEventBus) PublishEventNewRoundStep(rs EventDataRoundState) error {
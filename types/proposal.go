@@ -6,6 +6,7 @@ import (
 	"time"
 
 	cmn "github.com/tendermint/tendermint/libs/common"
+	tmtime "github.com/tendermint/tendermint/types/time"
 )
 
 var (
@@ -34,13 +35,22 @@ func NewProposal(height int64, round int, blockPartsHeader PartSetHeader, polRou
 	return &Proposal{
 		Height:           height,
 		Round:            round,
-		Timestamp:        time.Now().UTC(),
+		Timestamp:        tmtime.Now(),
 		BlockPartsHeader: blockPartsHeader,
 		POLRound:         polRound,
 		POLBlockID:       polBlockID,
 	}
 }
 
+// ValidateBasic performs basic validation that doesn't involve state data.
+// It checks that the timestamp is in range.
+func (p *Proposal) ValidateBasic() error {
+	if err := tmtime.Validate(p.Timestamp); err != nil {
+		return fmt.Errorf("Wrong Proposal.Timestamp: %v", err)
+	}
+	return nil
+}
+
 // String returns a string representation of the Proposal.
 func (p *Proposal) String() string {
 	return fmt.Sprintf("Proposal{%v/%v %v (%v,%v) %X @ %s}",
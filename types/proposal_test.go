@@ -74,6 +74,17 @@ func TestProposalVerifySignature(t *testing.T) {
 	require.True(t, valid)
 }
 
+func TestProposalValidateBasic(t *testing.T) {
+	require.NoError(t, testProposal.ValidateBasic())
+
+	prop := *testProposal
+	prop.Timestamp = time.Unix(-1, 0)
+	require.Error(t, prop.ValidateBasic())
+
+	prop.Timestamp = time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC)
+	require.Error(t, prop.ValidateBasic())
+}
+
 func BenchmarkProposalWriteSignBytes(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		testProposal.SignBytes("test_chain_id")
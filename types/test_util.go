@@ -1,6 +1,6 @@
 package types
 
-import "time"
+import tmtime "github.com/tendermint/tendermint/types/time"
 
 func MakeCommit(blockID BlockID, height int64, round int,
 	voteSet *VoteSet,
@@ -16,7 +16,7 @@ func MakeCommit(blockID BlockID, height int64, round int,
 			Round:            round,
 			Type:             VoteTypePrecommit,
 			BlockID:          blockID,
-			Timestamp:        time.Now().UTC(),
+			Timestamp:        tmtime.Now(),
 		}
 
 		_, err := signAddVote(validators[i], vote, voteSet)
@@ -0,0 +1,38 @@
+package time
+
+import (
+	"fmt"
+	"time"
+)
+
+var (
+	// MinTime is the earliest timestamp accepted in a header, vote or
+	// proposal: the Unix epoch.
+	MinTime = time.Unix(0, 0).UTC()
+	// MaxTime is the latest timestamp accepted in a header, vote or
+	// proposal: the end of year 9999, past which times don't round trip
+	// through RFC3339.
+	MaxTime = time.Date(9999, 12, 31, 23, 59, 59, 999999999, time.UTC)
+)
+
+// Now returns the current time in UTC with no monotonic component.
+func Now() time.Time {
+	return Canonical(time.Now())
+}
+
+// Canonical returns UTC time with no monotonic component.
+// Stripping the monotonic component is needed for time equality: two
+// times that are .Equal but carry different locations or monotonic clock
+// readings would otherwise encode (and hash) differently.
+// Precision is left at nanoseconds, which is what amino encodes.
+func Canonical(t time.Time) time.Time {
+	return t.Round(0).UTC()
+}
+
+// Validate returns an error if t is before MinTime or after MaxTime.
+func Validate(t time.Time) error {
+	if t.Before(MinTime) || t.After(MaxTime) {
+		return fmt.Errorf("Time %v is out of range [%v, %v]", t, MinTime, MaxTime)
+	}
+	return nil
+}
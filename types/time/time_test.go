@@ -0,0 +1,53 @@
+package time
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanonical(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		tokyo = time.FixedZone("UTC+9", 9*60*60)
+	}
+
+	// time.Now carries a monotonic clock reading, In() keeps it
+	now := time.Now()
+	local := now.In(tokyo)
+	assert.True(t, now.Equal(local))
+
+	c1, c2 := Canonical(now), Canonical(local)
+	assert.Equal(t, time.UTC, c1.Location())
+	assert.Equal(t, c1, c2, "canonical times should be identical, not just Equal")
+	assert.Equal(t, now.UnixNano(), c1.UnixNano())
+}
+
+func TestNow(t *testing.T) {
+	now := Now()
+	assert.Equal(t, time.UTC, now.Location())
+	assert.Equal(t, now, now.Round(0), "monotonic reading should be stripped")
+}
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		t     time.Time
+		valid bool
+	}{
+		{Now(), true},
+		{MinTime, true},
+		{MaxTime, true},
+		{time.Time{}, false},
+		{MinTime.Add(-time.Nanosecond), false},
+		{MaxTime.Add(time.Nanosecond), false},
+	}
+	for _, tc := range testCases {
+		err := Validate(tc.t)
+		if tc.valid {
+			assert.NoError(t, err, "%v", tc.t)
+		} else {
+			assert.Error(t, err, "%v", tc.t)
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	crypto "github.com/tendermint/tendermint/crypto"
 	cmn "github.com/tendermint/tendermint/libs/common"
+	tmtime "github.com/tendermint/tendermint/types/time"
 )
 
 var (
@@ -106,6 +107,15 @@ func (vote *Vote) String() string {
 		CanonicalTime(vote.Timestamp))
 }
 
+// ValidateBasic performs basic validation that doesn't involve state data.
+// It checks that the timestamp is in range.
+func (vote *Vote) ValidateBasic() error {
+	if err := tmtime.Validate(vote.Timestamp); err != nil {
+		return fmt.Errorf("Wrong Vote.Timestamp: %v", err)
+	}
+	return nil
+}
+
 func (vote *Vote) Verify(chainID string, pubKey crypto.PubKey) error {
 	if !bytes.Equal(pubKey.Address(), vote.ValidatorAddress) {
 		return ErrVoteInvalidValidatorAddress
@@ -119,3 +119,14 @@ func TestVoteVerify(t *testing.T) {
 		assert.Equal(t, ErrVoteInvalidSignature, err)
 	}
 }
+
+func TestVoteValidateBasic(t *testing.T) {
+	vote := examplePrecommit()
+	assert.NoError(t, vote.ValidateBasic())
+
+	vote.Timestamp = time.Time{}
+	assert.Error(t, vote.ValidateBasic())
+
+	vote.Timestamp = time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.Error(t, vote.ValidateBasic())
+}